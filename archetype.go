@@ -18,6 +18,18 @@ type Archetype interface {
 	Table() table.Table
 	// Generate creates entities with the specified components
 	Generate(count int, fromComponents ...any) error
+
+	// OnPut registers fn to run whenever an entity is moved into this
+	// archetype, e.g. by AddComponent/RemoveComponent changing its
+	// component set.
+	OnPut(fn func(Entity))
+	// OnRemove registers fn to run whenever an entity is moved out of this
+	// archetype.
+	OnRemove(fn func(Entity))
+	// OnReplace registers fn to run whenever an entity is moved into or out
+	// of this archetype, reporting both the archetype it came from and the
+	// one it moved to.
+	OnReplace(fn func(entity Entity, fromArch, toArch Archetype))
 }
 
 // ArchetypeImpl is the concrete implementation of the Archetype interface
@@ -26,6 +38,34 @@ type ArchetypeImpl struct {
 	table      table.Table
 	storage    *storage
 	components []Component
+
+	// changeTicks and addedTicks record, per component bit, the tick each
+	// row was last written to or added at. Maps are shared across copies
+	// of ArchetypeImpl since a Go map value is a reference.
+	changeTicks map[uint32][]uint64
+	addedTicks  map[uint32][]uint64
+
+	// maxChangeTick and maxAddedTick record, per component bit, the highest
+	// tick any row has ever been stamped at. Cursor.Initialize consults
+	// these to rule an archetype out of a Changed/Added filter in O(1)
+	// instead of scanning changeTicks/addedTicks row by row.
+	maxChangeTick map[uint32]uint64
+	maxAddedTick  map[uint32]uint64
+
+	// triggers holds this archetype's OnPut/OnRemove/OnReplace callbacks.
+	// It's a pointer, shared across copies of ArchetypeImpl the same way
+	// changeTicks and addedTicks are, so registering a trigger through one
+	// copy is visible to every other copy of the same archetype.
+	triggers *archetypeTriggers
+
+	// addEdges and removeEdges cache, per component ID, the archetype that
+	// AddComponent/RemoveComponent previously moved an entity to from this
+	// one. They're populated lazily on first transition, so a repeated
+	// AddComponent(c)/RemoveComponent(c) against this archetype skips
+	// recomputing the destination component set and re-looking it up by
+	// mask. Shared across copies of ArchetypeImpl the same way triggers is.
+	addEdges    map[uint32]archetypeID
+	removeEdges map[uint32]archetypeID
 }
 
 // newArchetypeImpl creates a new archetype with the given components
@@ -46,13 +86,112 @@ func newArchetype(
 		return ArchetypeImpl{}, err
 	}
 	return ArchetypeImpl{
-		storage:    sto,
-		components: components,
-		table:      tbl,
-		id:         id,
+		storage:       sto,
+		components:    components,
+		table:         tbl,
+		id:            id,
+		changeTicks:   make(map[uint32][]uint64),
+		addedTicks:    make(map[uint32][]uint64),
+		maxChangeTick: make(map[uint32]uint64),
+		maxAddedTick:  make(map[uint32]uint64),
+		triggers:      &archetypeTriggers{},
+		addEdges:      make(map[uint32]archetypeID),
+		removeEdges:   make(map[uint32]archetypeID),
 	}, nil
 }
 
+// stampChanged records tick as the last time the component at bit was
+// written for the given row, growing the tick column as needed
+func (a ArchetypeImpl) stampChanged(bit uint32, row int, tick uint64) {
+	a.changeTicks[bit] = growTickColumn(a.changeTicks[bit], row)
+	a.changeTicks[bit][row] = tick
+	if tick > a.maxChangeTick[bit] {
+		a.maxChangeTick[bit] = tick
+	}
+}
+
+// stampAdded records tick as the time the component at bit was added for
+// the given row, also stamping it as changed
+func (a ArchetypeImpl) stampAdded(bit uint32, row int, tick uint64) {
+	a.addedTicks[bit] = growTickColumn(a.addedTicks[bit], row)
+	a.addedTicks[bit][row] = tick
+	if tick > a.maxAddedTick[bit] {
+		a.maxAddedTick[bit] = tick
+	}
+	a.stampChanged(bit, row, tick)
+}
+
+// growTickColumn grows a tick column so that index row is addressable
+func growTickColumn(col []uint64, row int) []uint64 {
+	for len(col) <= row {
+		col = append(col, 0)
+	}
+	return col
+}
+
+// asArchetypeImpl normalizes a to the concrete ArchetypeImpl it wraps.
+// NewOrExistingArchetype returns a pointer for a newly created archetype
+// but a value for one that already existed, so callers that need the
+// concrete type (e.g. to reach an unexported method like stampAdded) have
+// to handle both forms; this is that in one place.
+func asArchetypeImpl(a Archetype) (ArchetypeImpl, bool) {
+	switch v := a.(type) {
+	case ArchetypeImpl:
+		return v, true
+	case *ArchetypeImpl:
+		return *v, true
+	default:
+		return ArchetypeImpl{}, false
+	}
+}
+
+// rowFor returns the table row backing comp within a, identified by
+// component bit (via storage.RowIndexFor) rather than Go reflect type, so
+// it still works for components that share an underlying Go type -- e.g.
+// every DynamicComponent, which all use the same fixed-size byte array as
+// their backing type. Assumes a.table.Rows() is ordered the same as
+// a.components, the order newArchetype builds the table's element types
+// in.
+func (a ArchetypeImpl) rowFor(comp Component) (table.Row, bool) {
+	bit := a.storage.RowIndexFor(comp)
+	for i, row := range a.table.Rows() {
+		if i >= len(a.components) {
+			break
+		}
+		if a.storage.RowIndexFor(a.components[i]) == bit {
+			return row, true
+		}
+	}
+	var zero table.Row
+	return zero, false
+}
+
+// addEdgeFor returns the archetype AddComponent(c) previously moved an
+// entity to from a, if that transition has happened before
+func (a ArchetypeImpl) addEdgeFor(c Component) (archetypeID, bool) {
+	id, ok := a.addEdges[a.storage.RowIndexFor(c)]
+	return id, ok
+}
+
+// setAddEdge caches dest as the archetype AddComponent(c) moves an entity
+// to from a
+func (a ArchetypeImpl) setAddEdge(c Component, dest archetypeID) {
+	a.addEdges[a.storage.RowIndexFor(c)] = dest
+}
+
+// removeEdgeFor returns the archetype RemoveComponent(c) previously moved
+// an entity to from a, if that transition has happened before
+func (a ArchetypeImpl) removeEdgeFor(c Component) (archetypeID, bool) {
+	id, ok := a.removeEdges[a.storage.RowIndexFor(c)]
+	return id, ok
+}
+
+// setRemoveEdge caches dest as the archetype RemoveComponent(c) moves an
+// entity to from a
+func (a ArchetypeImpl) setRemoveEdge(c Component, dest archetypeID) {
+	a.removeEdges[a.storage.RowIndexFor(c)] = dest
+}
+
 // ID returns the unique identifier of the ArchetypeImpl
 func (a ArchetypeImpl) ID() uint32 {
 	return uint32(a.id)
@@ -63,7 +202,28 @@ func (a ArchetypeImpl) Table() table.Table {
 	return a.table
 }
 
-// Generate creates the specified number of entities with optional component values
+// OnPut registers fn to run whenever an entity is moved into this archetype
+func (a ArchetypeImpl) OnPut(fn func(Entity)) {
+	a.triggers.addPut(fn)
+}
+
+// OnRemove registers fn to run whenever an entity is moved out of this
+// archetype
+func (a ArchetypeImpl) OnRemove(fn func(Entity)) {
+	a.triggers.addRemove(fn)
+}
+
+// OnReplace registers fn to run whenever an entity is moved into or out of
+// this archetype
+func (a ArchetypeImpl) OnReplace(fn func(entity Entity, fromArch, toArch Archetype)) {
+	a.triggers.addReplace(fn)
+}
+
+// Generate creates the specified number of entities with optional component
+// values. fromComponents items are ordinarily matched to a table row by Go
+// reflect type; a DynamicComponentValue is matched by component bit
+// instead (see ArchetypeImpl.setDynamicValue), since a dynamic component
+// has no Go type of its own to match against.
 func (a ArchetypeImpl) Generate(count int, fromComponents ...any) error {
 	entities, err := a.storage.NewEntities(count, a.components...)
 	if err != nil {
@@ -80,6 +240,15 @@ func (a ArchetypeImpl) Generate(count int, fromComponents ...any) error {
 	// Assign component values to each entity
 	for _, en := range entities {
 		for _, component := range fromComponents {
+			if dv, ok := component.(DynamicComponentValue); ok {
+				if err := a.setDynamicValue(en, dv); err != nil {
+					log.Debug("skipping dynamic component not in ArchetypeImpl",
+						"component_name", dv.Component.Name(),
+						"ArchetypeImpl_id", a.id,
+						"entity_index", en.Index())
+				}
+				continue
+			}
 			compType := reflect.TypeOf(component)
 			row, exists := reflectTypeToRow[compType]
 			if !exists {
@@ -26,14 +26,33 @@ type CacheLocation struct {
 	Index uint32
 }
 
-// SimpleCache implements the Cache interface with a slice-backed storage
+// SimpleCache implements the Cache interface with a slice-backed storage.
+// By default Register fails once the cache is at maxCapacity; when
+// lfuEvictionOn is set (see FactoryNewLFUCache) it instead evicts the
+// least-frequently-accessed item to make room, reusing that item's index.
 type SimpleCache[T any] struct {
-	mu          sync.RWMutex
-	items       []T
-	itemIndices map[string]int
-	maxCapacity int
+	mu            sync.RWMutex
+	items         []T
+	itemIndices   map[string]int
+	keysByIndex   map[int]string
+	accessCounts  []uint64
+	accessSeqs    []uint64
+	seqCounter    uint64
+	maxCapacity   int
+	lfuEvictionOn bool
 }
 
+// LFUCache is a SimpleCache configured for LFU eviction; FactoryNewLFUCache
+// returns this type rather than a plain SimpleCache so that callers have a
+// distinct type to depend on even though the eviction logic itself lives on
+// the embedded SimpleCache.
+type LFUCache[T any] struct {
+	SimpleCache[T]
+}
+
+// Ensure LFUCache implements the Cache interface
+var _ Cache[any] = &LFUCache[any]{}
+
 // GetIndex retrieves the index of an item by its key
 func (c *SimpleCache[T]) GetIndex(key string) (int, bool) {
 	c.mu.RLock()
@@ -44,32 +63,74 @@ func (c *SimpleCache[T]) GetIndex(key string) (int, bool) {
 
 // GetItem retrieves an item by its index
 func (c *SimpleCache[T]) GetItem(index int) T {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	item := c.items[index]
-	return item
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessCounts[index]++
+	c.seqCounter++
+	c.accessSeqs[index] = c.seqCounter
+	return c.items[index]
 }
 
 // GetItem32 retrieves an item by its uint32 index
 func (c *SimpleCache[T]) GetItem32(index uint32) T {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	item := c.items[index]
-	return item
+	return c.GetItem(int(index))
 }
 
-// Register adds a new item to the cache with the given key
-// Returns the index of the newly added item or an error if the cache is full
+// Register adds a new item to the cache with the given key.
+// If the cache is full and LFU eviction is enabled, the
+// least-frequently-accessed item is evicted to make room; its index is
+// reused for the new item. Otherwise returns an error once the cache is at
+// maximum capacity.
 func (c *SimpleCache[T]) Register(key string, item T) (int, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if len(c.itemIndices) >= c.maxCapacity {
+
+	if idx, ok := c.itemIndices[key]; ok {
+		c.items[idx] = item
+		return idx, nil
+	}
+
+	if len(c.itemIndices) < c.maxCapacity {
+		idx := len(c.items)
+		c.itemIndices[key] = idx
+		c.keysByIndex[idx] = key
+		c.items = append(c.items, item)
+		c.accessCounts = append(c.accessCounts, 0)
+		c.accessSeqs = append(c.accessSeqs, 0)
+		return idx, nil
+	}
+
+	if !c.lfuEvictionOn {
 		return -1, fmt.Errorf("cache at maximum capacity (%d)", c.maxCapacity)
 	}
-	idx := len(c.items)
-	c.itemIndices[key] = idx
-	c.items = append(c.items, item)
-	return idx, nil
+
+	evictIdx := c.leastFrequentlyUsedIndex()
+	delete(c.itemIndices, c.keysByIndex[evictIdx])
+	c.keysByIndex[evictIdx] = key
+	c.itemIndices[key] = evictIdx
+	c.items[evictIdx] = item
+	c.accessCounts[evictIdx] = 0
+	c.accessSeqs[evictIdx] = 0
+	return evictIdx, nil
+}
+
+// leastFrequentlyUsedIndex returns the index with the lowest access count,
+// breaking ties by least-recently-used (the lowest accessSeq, i.e. the
+// entry whose most recent GetItem/GetItem32 call happened longest ago, or
+// that has never been accessed at all). Caller must hold c.mu.
+func (c *SimpleCache[T]) leastFrequentlyUsedIndex() int {
+	lfuIdx := 0
+	lfuCount := c.accessCounts[0]
+	lfuSeq := c.accessSeqs[0]
+	for i, count := range c.accessCounts {
+		seq := c.accessSeqs[i]
+		if count < lfuCount || (count == lfuCount && seq < lfuSeq) {
+			lfuIdx = i
+			lfuCount = count
+			lfuSeq = seq
+		}
+	}
+	return lfuIdx
 }
 
 // Clear removes all items from the cache
@@ -77,5 +138,9 @@ func (c *SimpleCache[T]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.items = make([]T, 0, c.maxCapacity)
+	c.accessCounts = make([]uint64, 0, c.maxCapacity)
+	c.accessSeqs = make([]uint64, 0, c.maxCapacity)
+	c.seqCounter = 0
 	c.itemIndices = make(map[string]int)
+	c.keysByIndex = make(map[int]string)
 }
@@ -154,6 +154,93 @@ func TestCacheWithComplexTypes(t *testing.T) {
 	}
 }
 
+// TestLFUCacheEviction tests that a full LFU cache evicts the
+// least-frequently-accessed item instead of erroring
+func TestLFUCacheEviction(t *testing.T) {
+	const capacity = 3
+	cache := FactoryNewLFUCache[string](capacity)
+
+	for _, item := range []string{"a", "b", "c"} {
+		if _, err := cache.Register(item, item); err != nil {
+			t.Fatalf("Failed to register item %s: %v", item, err)
+		}
+	}
+
+	// Access "a" and "c" repeatedly so "b" becomes the least-frequently-used.
+	for i := 0; i < 5; i++ {
+		idx, _ := cache.GetIndex("a")
+		cache.GetItem(idx)
+		idx, _ = cache.GetIndex("c")
+		cache.GetItem(idx)
+	}
+
+	// Registering a new item should succeed by evicting "b", not error.
+	idx, err := cache.Register("d", "d")
+	if err != nil {
+		t.Fatalf("Register() on full LFU cache returned error: %v", err)
+	}
+
+	if _, found := cache.GetIndex("b"); found {
+		t.Errorf("expected \"b\" to be evicted, but it's still registered")
+	}
+	if got := cache.GetItem(idx); got != "d" {
+		t.Errorf("GetItem(%d) = %s, want d", idx, got)
+	}
+
+	// The other items should be untouched.
+	for _, item := range []string{"a", "c"} {
+		index, found := cache.GetIndex(item)
+		if !found {
+			t.Errorf("expected %s to still be registered", item)
+			continue
+		}
+		if got := cache.GetItem(index); got != item {
+			t.Errorf("GetItem(%d) = %s, want %s", index, got, item)
+		}
+	}
+}
+
+// TestLFUCacheTieBreaksByLeastRecentlyUsed tests that when two entries tie
+// on access count, eviction picks the one accessed longest ago rather than
+// the one with the lowest index
+func TestLFUCacheTieBreaksByLeastRecentlyUsed(t *testing.T) {
+	const capacity = 3
+	cache := FactoryNewLFUCache[string](capacity)
+
+	// "a" gets index 0, "b" gets index 1, but "b" is accessed before "a"
+	// below, so a naive lowest-index tie-break would wrongly evict "a"
+	// instead of the actually-least-recently-used "b".
+	for _, item := range []string{"a", "b", "c"} {
+		if _, err := cache.Register(item, item); err != nil {
+			t.Fatalf("Failed to register item %s: %v", item, err)
+		}
+	}
+
+	idx, _ := cache.GetIndex("b")
+	cache.GetItem(idx)
+	idx, _ = cache.GetIndex("a")
+	cache.GetItem(idx)
+
+	// Access "c" twice so it's no longer tied with "a"/"b" at the lowest
+	// count, leaving "a" and "b" as the only tie to break.
+	idx, _ = cache.GetIndex("c")
+	cache.GetItem(idx)
+	cache.GetItem(idx)
+
+	if _, err := cache.Register("d", "d"); err != nil {
+		t.Fatalf("Register() on full LFU cache returned error: %v", err)
+	}
+
+	if _, found := cache.GetIndex("b"); found {
+		t.Errorf("expected \"b\" to be evicted as least-recently-used, but it's still registered")
+	}
+	for _, item := range []string{"a", "c", "d"} {
+		if _, found := cache.GetIndex(item); !found {
+			t.Errorf("expected %s to still be registered", item)
+		}
+	}
+}
+
 // TestCacheConcurrentAccess tests concurrent access to the cache
 // Note: This is just a basic concurrent access test. More sophisticated tests might use the race detector.
 func TestCacheConcurrentAccess(t *testing.T) {
@@ -0,0 +1,175 @@
+package warehouse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// componentValue pairs a component with an optional value to assign it
+type componentValue struct {
+	component Component
+	value     any
+}
+
+// Changeset batches component additions, removals, and value overwrites
+// for a single entity into one atomic archetype transition. Calling
+// AddComponent/RemoveComponent repeatedly moves the entity once per call;
+// Changeset.Apply instead resolves the destination archetype once and
+// performs at most one TransferEntries, so N component changes cost one
+// archetype move instead of N.
+type Changeset struct {
+	entity  Entity
+	adds    []componentValue
+	removes []Component
+	sets    []componentValue
+}
+
+// NewChangeset starts a new atomic mutation batch for entity
+func NewChangeset(entity Entity) *Changeset {
+	return &Changeset{entity: entity}
+}
+
+// Add records a component to add, with no initial value
+func (cs *Changeset) Add(c Component) *Changeset {
+	cs.adds = append(cs.adds, componentValue{component: c})
+	return cs
+}
+
+// AddWithValue records a component to add along with its initial value
+func (cs *Changeset) AddWithValue(c Component, value any) *Changeset {
+	cs.adds = append(cs.adds, componentValue{component: c, value: value})
+	return cs
+}
+
+// Remove records a component to remove
+func (cs *Changeset) Remove(c Component) *Changeset {
+	cs.removes = append(cs.removes, c)
+	return cs
+}
+
+// Set records an overwrite of an existing component's value
+func (cs *Changeset) Set(c Component, value any) *Changeset {
+	cs.sets = append(cs.sets, componentValue{component: c, value: value})
+	return cs
+}
+
+// Apply performs every recorded add/remove/set as a single archetype
+// transition and, where change detection is in use, a single set of tick
+// stamps.
+func (cs *Changeset) Apply() error {
+	en, ok := cs.entity.(*entity)
+	if !ok {
+		return fmt.Errorf("changeset: entity %v does not support atomic batching", cs.entity)
+	}
+	if en.sto.Locked() {
+		return errors.New("storage is locked")
+	}
+
+	originTable := en.Table()
+
+	removed := make(map[uint32]bool, len(cs.removes))
+	for _, c := range cs.removes {
+		removed[en.sto.RowIndexFor(c)] = true
+	}
+
+	newComps := make([]Component, 0, len(en.components)+len(cs.adds))
+	for _, c := range en.components {
+		if !removed[en.sto.RowIndexFor(c)] {
+			newComps = append(newComps, c)
+		}
+	}
+	for _, add := range cs.adds {
+		exists := false
+		for _, existing := range newComps {
+			if existing.ID() == add.component.ID() {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			newComps = append(newComps, add.component)
+		}
+	}
+
+	var fromArchetype Archetype
+	if arch, ok := en.sto.archetypeFor(en.archetypeID); ok {
+		fromArchetype = arch
+	}
+
+	destArchetype, err := en.sto.NewOrExistingArchetype(newComps...)
+	if err != nil {
+		return err
+	}
+
+	if destArchetype.Table() != originTable {
+		destLenBefore := destArchetype.Table().Length()
+		if err := originTable.TransferEntries(destArchetype.Table(), en.Index()); err != nil {
+			return err
+		}
+		if newEntry, err := destArchetype.Table().Entry(destLenBefore); err == nil && newEntry.ID() != en.id {
+			rebindEntityID(en, newEntry.ID())
+		}
+		en.sto.fireArchetypeTriggers(en, fromArchetype, destArchetype)
+	}
+
+	for _, c := range cs.removes {
+		en.sto.recordRemoval(en.sto.RowIndexFor(c), en.id)
+		spatialRemove(en.sto, c, en.id)
+	}
+
+	en.components = newComps
+	en.archetypeID = archetypeID(destArchetype.ID())
+	en.row = uint32(en.Index())
+
+	tick := en.sto.CurrentTick()
+	arch, hasArch := asArchetypeImpl(destArchetype)
+
+	for _, add := range cs.adds {
+		if hasArch {
+			arch.stampAdded(en.sto.RowIndexFor(add.component), en.Index(), tick)
+		}
+		if add.value != nil {
+			if err := setRowValue(destArchetype, en.Index(), add.component, add.value); err != nil {
+				return err
+			}
+			spatialReindex(en.sto, add.component, en.id, add.value)
+		}
+	}
+	for _, set := range cs.sets {
+		if err := setRowValue(destArchetype, en.Index(), set.component, set.value); err != nil {
+			return err
+		}
+		if hasArch {
+			arch.stampChanged(en.sto.RowIndexFor(set.component), en.Index(), tick)
+		}
+		spatialReindex(en.sto, set.component, en.id, set.value)
+	}
+
+	return nil
+}
+
+// setRowValue writes value into the table row backing component c at row
+func setRowValue(arch Archetype, row int, c Component, value any) error {
+	valueType := reflect.TypeOf(value)
+	for _, tblRow := range arch.Table().Rows() {
+		if tblRow.Type().Elem() == valueType {
+			reflect.Value(tblRow).Index(row).Set(reflect.ValueOf(value))
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value type %v for component %v", valueType, c.Type())
+}
+
+// getRowValue reads the table row value backing component c at row, or nil
+// if tbl doesn't carry c
+func getRowValue(tbl table.Table, row int, c Component) any {
+	for _, tblRow := range tbl.Rows() {
+		if tblRow.Type().Elem() == c.Type() {
+			return reflect.Value(tblRow).Index(row).Interface()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestChangesetAtomicMutation tests that a Changeset applies adds, removes,
+// and sets as a single archetype transition
+func TestChangesetAtomicMutation(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+	healthComp := FactoryNewComponent[Health]()
+
+	entities, err := storage.NewEntities(1, posComp, healthComp)
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	entity := entities[0]
+
+	err = NewChangeset(entity).
+		AddWithValue(velComp, Velocity{X: 1, Y: 2}).
+		Remove(healthComp).
+		Set(posComp, Position{X: 9, Y: 9}).
+		Apply()
+	if err != nil {
+		t.Fatalf("Changeset.Apply() failed: %v", err)
+	}
+
+	if entity.Table().Contains(healthComp) {
+		t.Errorf("expected health component to be removed")
+	}
+	if !entity.Table().Contains(velComp) {
+		t.Errorf("expected velocity component to be added")
+	}
+
+	velPtr := velComp.GetFromEntity(entity)
+	if velPtr.X != 1 || velPtr.Y != 2 {
+		t.Errorf("Velocity = %+v, want {1 2}", *velPtr)
+	}
+
+	posPtr := posComp.GetFromEntity(entity)
+	if posPtr.X != 9 || posPtr.Y != 9 {
+		t.Errorf("Position = %+v, want {9 9}", *posPtr)
+	}
+}
+
+// TestChangesetLockedStorage tests that Apply refuses to run against a
+// locked storage
+func TestChangesetLockedStorage(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	entity := entities[0]
+
+	storage.AddLock(1)
+	err = NewChangeset(entity).Add(velComp).Apply()
+	if err == nil {
+		t.Errorf("expected error applying Changeset against locked storage")
+	}
+}
@@ -1,6 +1,10 @@
 package warehouse
 
-import "github.com/TheBitDrifter/table"
+import (
+	"reflect"
+
+	"github.com/TheBitDrifter/table"
+)
 
 // AccessibleComponent extends a base Component with table-based accessibility
 // It provides methods to retrieve components using different access patterns
@@ -36,3 +40,106 @@ func (c AccessibleComponent[T]) CheckCursor(cursor *Cursor) bool {
 func (c AccessibleComponent[T]) GetFromEntity(entity Entity) *T {
 	return c.Get(entity.Index(), entity.Table())
 }
+
+// GetFast retrieves a component value using the entity's cached archetype
+// location, skipping the mask-based archetype lookup GetFromEntity does.
+// The row itself is still resolved live via Index(), since a sibling
+// entity's destruction can swap-remove this entity to a different row
+// without anything updating a cached one. Falls back to GetFromEntity if
+// the entity doesn't carry a cached archetype.
+func (c AccessibleComponent[T]) GetFast(ent Entity) *T {
+	en, ok := ent.(*entity)
+	if !ok {
+		return c.GetFromEntity(ent)
+	}
+	arch, ok := en.sto.archetypeFor(en.archetypeID)
+	if !ok {
+		return c.GetFromEntity(ent)
+	}
+	return c.Get(en.Index(), arch.table)
+}
+
+// SetChanged stamps entity's row for this component as changed at the
+// storage's current tick. Use this after mutating a pointer returned by
+// GetFromEntity/GetFromCursor/GetFast directly, since those don't go
+// through SetComponent and so don't stamp the change themselves.
+func (c AccessibleComponent[T]) SetChanged(ent Entity) {
+	en, ok := ent.(*entity)
+	if !ok {
+		return
+	}
+	arch, ok := en.sto.archetypeFor(en.archetypeID)
+	if !ok {
+		return
+	}
+	arch.stampChanged(en.sto.RowIndexFor(c), en.Index(), en.sto.CurrentTick())
+}
+
+// GetTick returns the tick this component was last changed at for entity,
+// or 0 if it's never been stamped.
+func (c AccessibleComponent[T]) GetTick(ent Entity) uint64 {
+	en, ok := ent.(*entity)
+	if !ok {
+		return 0
+	}
+	arch, ok := en.sto.archetypeFor(en.archetypeID)
+	if !ok {
+		return 0
+	}
+	col, ok := arch.changeTicks[en.sto.RowIndexFor(c)]
+	if !ok || en.Index() >= len(col) {
+		return 0
+	}
+	return col[en.Index()]
+}
+
+// OptionalFromCursor returns this component's value for the entity at the
+// cursor's current position, and whether the current archetype carries it
+// at all. Use this for a component added to a query via Maybe instead of
+// as a plain And/Or item: presence is read from the fetch plan
+// Cursor.Initialize precomputed for the query's Maybe'd components, so
+// unlike GetFromCursorSafe it doesn't re-derive presence from the
+// archetype's mask on every call.
+func (c AccessibleComponent[T]) OptionalFromCursor(cursor *Cursor) (*T, bool) {
+	bit := cursor.storage.RowIndexFor(c)
+	present, ok := cursor.optionalPresence[cursor.currentArchetype.id][bit]
+	if !ok || !present {
+		return nil, false
+	}
+	return c.GetFromCursor(cursor), true
+}
+
+// SliceFromChunk returns the contiguous component slice for just view's
+// rows, i.e. Column(view)[view.Start() : view.Start()+view.Len()]. Use this
+// instead of Column for a ChunkView that may be a sub-range of its
+// archetype's table, e.g. one yielded by Cursor.ParallelChunks. Returns nil
+// if the chunk's archetype doesn't carry this component.
+func (c AccessibleComponent[T]) SliceFromChunk(view ChunkView) []T {
+	full := c.Column(view)
+	if full == nil {
+		return nil
+	}
+	end := view.start + view.length
+	if end > len(full) {
+		end = len(full)
+	}
+	return full[view.start:end]
+}
+
+// Column returns the whole contiguous component slice backing the chunk,
+// e.g. for i := range positions { positions[i].X += ... }. Returns nil if
+// the chunk's archetype doesn't carry this component.
+func (c AccessibleComponent[T]) Column(view ChunkView) []T {
+	var zero T
+	elemType := reflect.TypeOf(zero)
+	for _, row := range view.table.Rows() {
+		if row.Type().Elem() == elemType {
+			vals, ok := reflect.Value(row).Interface().([]T)
+			if !ok {
+				return nil
+			}
+			return vals
+		}
+	}
+	return nil
+}
@@ -2,10 +2,65 @@ package warehouse
 
 import (
 	"iter"
+	"sync"
 
+	"github.com/TheBitDrifter/mask"
 	"github.com/TheBitDrifter/table"
 )
 
+// chunkIterationLockBit is the reserved storage lock bit held for the
+// duration of ForEachChunkParallel, preventing structural changes
+// (archetype moves, entity creation/destruction) while chunks are in
+// flight on worker goroutines.
+const chunkIterationLockBit uint32 = 31
+
+// cursorLockBit is the reserved storage lock bit held between Initialize
+// and Reset, preventing structural changes while a Cursor holds a cached
+// list of matched archetypes.
+const cursorLockBit uint32 = 28
+
+// ChunkView exposes a contiguous block of a single matched archetype's
+// table: either the whole table (from Cursor.Chunks) or a sub-range of it
+// of at most some chunkSize rows (from Cursor.ParallelChunks), rather than
+// one entity at a time. Use AccessibleComponent.Column for a whole-table
+// ChunkView, or AccessibleComponent.SliceFromChunk for a ChunkView that may
+// be a sub-range, to fetch a typed, contiguous component slice.
+type ChunkView struct {
+	archetype ArchetypeImpl
+	table     table.Table
+	start     int
+	length    int
+}
+
+// Len returns the number of entities in this chunk
+func (v ChunkView) Len() int {
+	return v.length
+}
+
+// Start returns the row this chunk begins at within its archetype's table
+func (v ChunkView) Start() int {
+	return v.start
+}
+
+// Table returns the underlying table backing this chunk. The chunk may
+// only cover a sub-range of it; see Start and Len.
+func (v ChunkView) Table() table.Table {
+	return v.table
+}
+
+// EntityIDs returns the entity ID for every row in the chunk, in row order
+func (v ChunkView) EntityIDs() []table.EntryID {
+	ids := make([]table.EntryID, v.length)
+	for i := range ids {
+		entry, err := v.table.Entry(v.start + i)
+		if err != nil {
+			continue
+		}
+		ids[i] = entry.ID()
+	}
+	return ids
+}
+
 // Ensure Cursor implements iCursor interface
 var _ iCursor = &Cursor{}
 
@@ -26,6 +81,48 @@ type Cursor struct {
 
 	initialized     bool
 	matchedStorages []ArchetypeImpl
+
+	// presetMatchedStorages, when non-nil, is a QueryState's cached matched
+	// archetype slice. Initialize reuses it directly instead of scanning
+	// and re-evaluating every archetype in storage. Set via
+	// Factory.NewCursorFromState.
+	presetMatchedStorages []ArchetypeImpl
+
+	// tickFilters are the Added/Changed/Removed terms found in query,
+	// applied at row granularity on top of the tree's archetype-level
+	// Evaluate. sinceTick is the tick this cursor last finished an
+	// iteration at; it persists across Reset so repeated runs only see
+	// what's new.
+	tickFilters []*tickNode
+	sinceTick   uint64
+
+	// relationFilters are the HasRelation/HasAnyRelation terms found in
+	// query, applied at row granularity on top of the tree's archetype-level
+	// Evaluate, the same way tickFilters are.
+	relationFilters []*relationNode
+
+	// modifiedFilters are the Modified terms found in query, applied at row
+	// granularity the same way tickFilters are, but against each node's own
+	// explicit since tick rather than c.sinceTick.
+	modifiedFilters []*modifiedNode
+
+	// optionalNodes are the Maybe terms found in query. They don't filter
+	// anything; optionalPresence is the fetch plan Initialize precomputes
+	// from them, one presence map per matched archetype, so
+	// AccessibleComponent.OptionalFromCursor's hot-loop presence check is a
+	// lookup against work already done instead of re-deriving it from the
+	// archetype's mask on every row.
+	optionalNodes    []*optionalNode
+	optionalPresence map[archetypeID]map[uint32]bool
+
+	// spatialNode is the Near/WithinRadius term found in query, if any. When
+	// set, Next iterates spatialResults (nearest-first candidates from the
+	// component's HNSW index) instead of walking matchedStorages in
+	// archetype order.
+	spatialNode     *spatialNode
+	spatialResults  []hnswCandidate
+	spatialIdx      int
+	currentDistance float64
 }
 
 // newCursor creates a new cursor for the given query and storage
@@ -36,8 +133,70 @@ func newCursor(query QueryNode, storage Storage) *Cursor {
 	}
 }
 
-// Next advances to the next entity and returns whether one exists
+// Next advances to the next entity matching the query, including any
+// Added/Changed/Removed tick filters, and returns whether one exists. If
+// the query carries a Near/WithinRadius term, entities are visited in
+// nearest-first order instead of archetype order.
 func (c *Cursor) Next() bool {
+	if !c.initialized {
+		c.Initialize()
+	}
+	if c.spatialNode != nil {
+		return c.spatialStep()
+	}
+	for c.step() {
+		if c.matchesTickFilters() && c.matchesRelationFilters() && c.matchesModifiedFilters() {
+			return true
+		}
+	}
+	return false
+}
+
+// spatialStep advances through spatialResults in nearest-first order,
+// skipping candidates whose current archetype no longer satisfies the rest
+// of the query (e.g. the entity moved since the index was last rebuilt)
+func (c *Cursor) spatialStep() bool {
+	for c.spatialIdx < len(c.spatialResults) {
+		cand := c.spatialResults[c.spatialIdx]
+		c.spatialIdx++
+
+		ent, err := c.storage.Entity(int(cand.id))
+		if err != nil {
+			continue
+		}
+		concrete, ok := ent.(*entity)
+		if !ok {
+			continue
+		}
+		arch, ok := c.storage.archetypeFor(concrete.archetypeID)
+		if !ok || !c.query.Evaluate(arch, c.storage) {
+			continue
+		}
+
+		c.currentArchetype = arch
+		// The cached row goes stale after a sibling's swap-remove, so
+		// resolve it live.
+		c.entityIndex = concrete.Index() + 1
+		c.remaining = arch.table.Length()
+		c.currentDistance = cand.dist
+
+		if c.matchesTickFilters() && c.matchesRelationFilters() && c.matchesModifiedFilters() {
+			return true
+		}
+	}
+	c.Reset()
+	return false
+}
+
+// CurrentDistance returns the distance of the current entity from the
+// Near/WithinRadius query point. Only meaningful while iterating a query
+// that carries such a term.
+func (c *Cursor) CurrentDistance() float64 {
+	return c.currentDistance
+}
+
+// step advances to the next row without applying tick filters
+func (c *Cursor) step() bool {
 	if c.entityIndex < c.remaining {
 		c.entityIndex++
 		return true
@@ -45,6 +204,93 @@ func (c *Cursor) Next() bool {
 	return c.advance()
 }
 
+// matchesTickFilters reports whether the current row passes every
+// Added/Changed/Removed term collected from the query
+func (c *Cursor) matchesTickFilters() bool {
+	if len(c.tickFilters) == 0 {
+		return true
+	}
+	row := c.entityIndex - 1
+	for _, n := range c.tickFilters {
+		if !n.rowMatches(c.storage, c.currentArchetype, row, c.sinceTick) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesRelationFilters reports whether the current row passes every
+// HasRelation/HasAnyRelation term collected from the query
+func (c *Cursor) matchesRelationFilters() bool {
+	if len(c.relationFilters) == 0 {
+		return true
+	}
+	row := c.entityIndex - 1
+	for _, n := range c.relationFilters {
+		if !n.rowMatches(c.storage, c.currentArchetype, row) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesModifiedFilters reports whether the current row passes every
+// Modified term collected from the query
+func (c *Cursor) matchesModifiedFilters() bool {
+	if len(c.modifiedFilters) == 0 {
+		return true
+	}
+	row := c.entityIndex - 1
+	for _, n := range c.modifiedFilters {
+		if !n.rowMatches(c.storage, c.currentArchetype, row) {
+			return false
+		}
+	}
+	return true
+}
+
+// archetypeMayMatchTickFilters reports whether arch could possibly pass
+// every Added/Changed/Removed/Modified term collected from the query,
+// checking each filter's O(1) archetype-level max tick so Initialize can
+// drop an archetype from matchedStorages up front instead of only ever
+// ruling it out one row at a time via Next.
+func (c *Cursor) archetypeMayMatchTickFilters(arch ArchetypeImpl) bool {
+	for _, n := range c.tickFilters {
+		if !n.archetypeMayMatch(c.storage, arch, c.sinceTick) {
+			return false
+		}
+	}
+	for _, n := range c.modifiedFilters {
+		if !n.archetypeMayMatch(c.storage, arch) {
+			return false
+		}
+	}
+	return true
+}
+
+// planOptionalFetch records, for arch, which of c.optionalNodes' components
+// it carries, keyed by component bit. Called once per matched archetype
+// from Initialize rather than once per row, this is the fetch plan
+// AccessibleComponent.OptionalFromCursor consults instead of re-deriving
+// presence from the archetype's mask on every entity.
+func (c *Cursor) planOptionalFetch(arch ArchetypeImpl) {
+	if len(c.optionalNodes) == 0 {
+		return
+	}
+	if c.optionalPresence == nil {
+		c.optionalPresence = make(map[archetypeID]map[uint32]bool)
+	}
+	archeMask := arch.table.(mask.Maskable).Mask()
+	presence := make(map[uint32]bool, len(c.optionalNodes))
+	for _, n := range c.optionalNodes {
+		bit := c.storage.RowIndexFor(n.component)
+		var bitMask mask.Mask
+		bitMask.Mark(bit)
+		presence[bit] = archeMask.ContainsAll(bitMask)
+	}
+	c.optionalPresence[arch.id] = presence
+}
+
 // advance moves to the next available archetype with entities
 func (c *Cursor) advance() bool {
 	if !c.initialized {
@@ -91,22 +337,147 @@ func (c *Cursor) Entities() iter.Seq2[int, table.Table] {
 	}
 }
 
+// Chunks returns an iterator over matched archetypes as whole ChunkViews,
+// for systems that want to operate on contiguous component columns instead
+// of walking entities one at a time via Next.
+func (c *Cursor) Chunks() iter.Seq2[ArchetypeImpl, ChunkView] {
+	return func(yield func(ArchetypeImpl, ChunkView) bool) {
+		c.Initialize()
+
+		for _, arch := range c.matchedStorages {
+			view := ChunkView{archetype: arch, table: arch.table, length: arch.table.Length()}
+			if !yield(arch, view) {
+				c.Reset()
+				return
+			}
+		}
+
+		c.Reset()
+	}
+}
+
+// ParallelChunks returns an iterator over matched archetypes split into
+// sub-chunks of at most chunkSize rows each, for callers that want
+// finer-grained units of work than one chunk per archetype — chiefly
+// Storage.RunParallel, which dispatches each yielded ChunkView to a worker
+// pool. A chunkSize <= 0 yields one chunk per archetype, the same chunking
+// Chunks uses.
+func (c *Cursor) ParallelChunks(chunkSize int) iter.Seq[ChunkView] {
+	return func(yield func(ChunkView) bool) {
+		c.Initialize()
+
+		for _, arch := range c.matchedStorages {
+			total := arch.table.Length()
+			size := chunkSize
+			if size <= 0 {
+				size = total
+			}
+			for start := 0; start < total; start += size {
+				length := size
+				if start+length > total {
+					length = total - start
+				}
+				view := ChunkView{archetype: arch, table: arch.table, start: start, length: length}
+				if !yield(view) {
+					c.Reset()
+					return
+				}
+			}
+		}
+
+		c.Reset()
+	}
+}
+
+// ForEachChunkParallel fans the matched archetype chunks out over a pool of
+// workers, holding the storage lock for the duration so systems that touch
+// disjoint archetypes can iterate them concurrently without structural
+// changes (entity creation/destruction, component add/remove) racing the
+// workers. fn is called once per matched archetype with that archetype's
+// ChunkView; calls happen concurrently across workers, so fn must not
+// mutate state shared across chunks without its own synchronization.
+func (c *Cursor) ForEachChunkParallel(workers int, fn func(ChunkView)) {
+	c.storage.AddLock(chunkIterationLockBit)
+	defer c.storage.RemoveLock(chunkIterationLockBit)
+
+	c.Initialize()
+	chunks := make([]ChunkView, len(c.matchedStorages))
+	for i, arch := range c.matchedStorages {
+		chunks[i] = ChunkView{archetype: arch, table: arch.table, length: arch.table.Length()}
+	}
+	c.Reset()
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+	if workers <= 1 {
+		for _, chunk := range chunks {
+			fn(chunk)
+		}
+		return
+	}
+
+	work := make(chan ChunkView)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for chunk := range work {
+				fn(chunk)
+			}
+		}()
+	}
+	for _, chunk := range chunks {
+		work <- chunk
+	}
+	close(work)
+	wg.Wait()
+}
+
 // Initialize sets up the cursor by finding matching archetypes
 func (c *Cursor) Initialize() {
 	if c.initialized {
 		return
 	}
 
-	c.storage.AddLock()
-	c.matchedStorages = make([]ArchetypeImpl, 0)
+	c.storage.AddLock(cursorLockBit)
+	c.tickFilters = collectTickNodes(c.query)
+	c.relationFilters = collectRelationNodes(c.query)
+	c.modifiedFilters = collectModifiedNodes(c.query)
+	c.optionalNodes = collectOptionalNodes(c.query)
+	c.spatialNode = collectSpatialNode(c.query)
 
-	// Find all matching archetypes
-	for _, arch := range c.storage.Archetypes() {
-		if c.query.Evaluate(arch, c.storage) {
+	if c.presetMatchedStorages != nil {
+		// A QueryState already evaluated every archetype for this query and
+		// keeps the result current as storage creates new ones; reuse it
+		// instead of re-scanning storage.Archetypes(). archetypeMayMatchTickFilters
+		// isn't applied here (QueryState doesn't track tick state), so a
+		// Changed/Added/Modified query sourced from a QueryState relies on
+		// Next's row-level matchesTickFilters for correctness rather than
+		// this archetype-level short circuit.
+		c.matchedStorages = make([]ArchetypeImpl, 0, len(c.presetMatchedStorages))
+		for _, arch := range c.presetMatchedStorages {
 			c.matchedStorages = append(c.matchedStorages, arch)
+			c.planOptionalFetch(arch)
+		}
+	} else {
+		c.matchedStorages = make([]ArchetypeImpl, 0)
+		for _, arch := range c.storage.Archetypes() {
+			if c.query.Evaluate(arch, c.storage) && c.archetypeMayMatchTickFilters(arch) {
+				c.matchedStorages = append(c.matchedStorages, arch)
+				c.planOptionalFetch(arch)
+			}
 		}
 	}
 
+	if c.spatialNode != nil {
+		c.spatialResults = c.spatialNode.results(c.storage)
+	}
+
 	if len(c.matchedStorages) > 0 {
 		c.storageIndex = 0
 		c.currentArchetype = c.matchedStorages[0]
@@ -116,14 +487,27 @@ func (c *Cursor) Initialize() {
 	c.initialized = true
 }
 
-// Reset clears cursor state and releases the storage lock
+// Reset clears cursor state and releases the storage lock. If the query
+// carried Added/Changed/Removed terms, sinceTick is advanced to the
+// storage's current tick so the next iteration only sees what's new.
 func (c *Cursor) Reset() {
+	if len(c.tickFilters) > 0 {
+		c.sinceTick = c.storage.CurrentTick()
+	}
 	c.storageIndex = 0
 	c.entityIndex = 0
 	c.remaining = 0
 	c.matchedStorages = nil
+	c.tickFilters = nil
+	c.relationFilters = nil
+	c.modifiedFilters = nil
+	c.optionalNodes = nil
+	c.optionalPresence = nil
+	c.spatialNode = nil
+	c.spatialResults = nil
+	c.spatialIdx = 0
 	c.initialized = false
-	c.storage.PopLock()
+	c.storage.RemoveLock(cursorLockBit)
 }
 
 // CurrentEntity returns the entity at the current cursor position
@@ -156,6 +540,25 @@ func (c *Cursor) RemainingInArchetype() int {
 	return c.remaining - c.entityIndex
 }
 
+// GetByEntity resolves an entity straight to its archetype table using the
+// entity's cached archetype, with no linear scan over matched archetypes
+// and no mask evaluation. The row itself is still resolved live via
+// Index(), since a sibling entity's destruction can swap-remove this
+// entity to a different row without anything updating a cached one.
+// Returns false if the entity has no cached archetype (e.g. it was never
+// placed by this package's storage).
+func (c *Cursor) GetByEntity(ent Entity) (table.Table, int, bool) {
+	en, ok := ent.(*entity)
+	if !ok {
+		return nil, 0, false
+	}
+	arch, ok := c.storage.archetypeFor(en.archetypeID)
+	if !ok {
+		return nil, 0, false
+	}
+	return arch.table, en.Index(), true
+}
+
 // TotalMatched returns the total number of entities matching the query
 func (c *Cursor) TotalMatched() int {
 	if !c.initialized {
@@ -0,0 +1,159 @@
+package warehouse
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// dynamicComponentMaxSize bounds how large a single DynamicComponent's raw
+// value can be. table.FactoryNewElementType/FactoryNewAccessor are generic
+// over a compile-time Go type, so a runtime-chosen byte size can't become
+// its own column type the way FactoryNewComponent[T]'s T does; every
+// DynamicComponent instead shares one fixed-size byte array as its backing
+// type (still getting its own distinct identity/mask bit per
+// NewDynamicComponent call), and is limited to a size that fits within it.
+const dynamicComponentMaxSize = 256
+
+// dynamicComponentStorage is the backing element type every
+// DynamicComponent's table column uses.
+type dynamicComponentStorage [dynamicComponentMaxSize]byte
+
+// DynamicComponent identifies a runtime-defined component: one registered
+// by name/size/align rather than a compile-time Go type parameter, for
+// scripting/plugin layers (Lua, WASM, JSON-driven content) that discover
+// component shapes at runtime instead of compile time. Use DynamicAccessor
+// to read or write its raw bytes for an entity or cursor position.
+type DynamicComponent struct {
+	Component
+	name  string
+	size  uintptr
+	align uintptr
+}
+
+// Name returns the name this DynamicComponent was registered under.
+func (d DynamicComponent) Name() string { return d.name }
+
+// ByteSize returns the byte size this DynamicComponent was registered
+// with. Named distinctly from the embedded Component's Size() uint32
+// (from table.ElementType) -- reusing that name here would mean
+// DynamicComponent no longer satisfies Component at all.
+func (d DynamicComponent) ByteSize() uintptr { return d.size }
+
+// Align returns the alignment this DynamicComponent was registered with.
+func (d DynamicComponent) Align() uintptr { return d.align }
+
+// NewDynamicComponent registers a component by name/size/align instead of
+// a compile-time Go type parameter. size must not exceed
+// dynamicComponentMaxSize; align is recorded for callers that need it
+// (e.g. to lay out a foreign struct inside the raw bytes) but isn't
+// enforced here, since every DynamicComponent already shares one
+// byte-array backing type regardless of align.
+func (f factory) NewDynamicComponent(name string, size uintptr, align uintptr) (DynamicComponent, error) {
+	if size > dynamicComponentMaxSize {
+		return DynamicComponent{}, fmt.Errorf("warehouse: dynamic component %q size %d exceeds max %d", name, size, dynamicComponentMaxSize)
+	}
+	iden := table.FactoryNewElementType[dynamicComponentStorage]()
+	c := DynamicComponent{
+		Component: iden,
+		name:      name,
+		size:      size,
+		align:     align,
+	}
+	registerComponentType(c.Component)
+	return c, nil
+}
+
+// DynamicComponentValue pairs a DynamicComponent with a raw byte value to
+// assign during ArchetypeImpl.Generate, alongside Generate's existing
+// reflect-typed fromComponents path, which can't express a dynamic
+// component's value since it has no Go type of its own to match against a
+// table row.
+type DynamicComponentValue struct {
+	Component DynamicComponent
+	Value     []byte
+}
+
+// DynamicAccessor reads and writes a DynamicComponent's raw bytes for a
+// given entity or cursor position, returning an unsafe.Pointer into the
+// backing column plus the registered size instead of a typed *T the way
+// AccessibleComponent[T] does, since a dynamic component has no Go type of
+// its own.
+type DynamicAccessor struct {
+	component DynamicComponent
+}
+
+// NewDynamicAccessor creates a DynamicAccessor for component.
+func NewDynamicAccessor(component DynamicComponent) DynamicAccessor {
+	return DynamicAccessor{component: component}
+}
+
+// GetFromEntity returns an unsafe.Pointer to component's raw bytes for
+// entity, and its registered size, or (nil, 0) if entity's archetype
+// doesn't carry it.
+func (a DynamicAccessor) GetFromEntity(ent Entity) (unsafe.Pointer, uintptr) {
+	en, ok := ent.(*entity)
+	if !ok {
+		return nil, 0
+	}
+	arch, ok := en.sto.archetypeFor(en.archetypeID)
+	if !ok {
+		return nil, 0
+	}
+	return a.at(arch, en.Index())
+}
+
+// GetFromCursor returns an unsafe.Pointer to component's raw bytes for the
+// entity at the cursor position, and its registered size, or (nil, 0) if
+// the current archetype doesn't carry it.
+func (a DynamicAccessor) GetFromCursor(cursor *Cursor) (unsafe.Pointer, uintptr) {
+	return a.at(cursor.currentArchetype, cursor.entityIndex-1)
+}
+
+// at resolves a.component's raw bytes for row within arch, identifying the
+// right table row by component bit (via storage.RowIndexFor) rather than
+// Go reflect type, since every DynamicComponent shares the same
+// dynamicComponentStorage backing type and so can't be told apart by type
+// alone.
+func (a DynamicAccessor) at(arch ArchetypeImpl, row int) (unsafe.Pointer, uintptr) {
+	col, ok := a.column(arch)
+	if !ok || row < 0 || row >= len(col) {
+		return nil, 0
+	}
+	return unsafe.Pointer(&col[row][0]), a.component.size
+}
+
+// column returns the raw backing column for a.component within arch.
+func (a DynamicAccessor) column(arch ArchetypeImpl) ([]dynamicComponentStorage, bool) {
+	tblRow, ok := arch.rowFor(a.component)
+	if !ok {
+		return nil, false
+	}
+	vals, ok := reflect.Value(tblRow).Interface().([]dynamicComponentStorage)
+	if !ok {
+		return nil, false
+	}
+	return vals, true
+}
+
+// setDynamicValue writes dv.Value into en's row of dv.Component's backing
+// column within a, used by Generate's DynamicComponentValue path.
+func (a ArchetypeImpl) setDynamicValue(en Entity, dv DynamicComponentValue) error {
+	accessor := DynamicAccessor{component: dv.Component}
+	col, ok := accessor.column(a)
+	if !ok {
+		return fmt.Errorf("dynamic component %q not in ArchetypeImpl", dv.Component.Name())
+	}
+	if en.Index() < 0 || en.Index() >= len(col) {
+		return fmt.Errorf("entity index %d out of range for ArchetypeImpl", en.Index())
+	}
+	if uintptr(len(dv.Value)) > dv.Component.size {
+		return fmt.Errorf("dynamic component %q value of %d bytes exceeds registered size %d", dv.Component.Name(), len(dv.Value), dv.Component.size)
+	}
+	var zero dynamicComponentStorage
+	col[en.Index()] = zero
+	copy(col[en.Index()][:], dv.Value)
+	return nil
+}
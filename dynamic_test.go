@@ -0,0 +1,112 @@
+package warehouse
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestDynamicComponentGenerateAndRead tests that a DynamicComponent can be
+// assigned a raw value via Generate and read back through DynamicAccessor
+func TestDynamicComponentGenerateAndRead(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	health, err := Factory.NewDynamicComponent("Health", 4, 4)
+	if err != nil {
+		t.Fatalf("NewDynamicComponent failed: %v", err)
+	}
+
+	arch, err := storage.NewOrExistingArchetype(health)
+	if err != nil {
+		t.Fatalf("NewOrExistingArchetype failed: %v", err)
+	}
+
+	value := []byte{1, 2, 3, 4}
+	if err := arch.Generate(1, DynamicComponentValue{Component: health, Value: value}); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(health), storage)
+	if !cursor.Next() {
+		t.Fatalf("expected one matching entity")
+	}
+	entity, err := cursor.CurrentEntity()
+	if err != nil {
+		t.Fatalf("CurrentEntity failed: %v", err)
+	}
+
+	accessor := NewDynamicAccessor(health)
+	ptr, size := accessor.GetFromEntity(entity)
+	if ptr == nil {
+		t.Fatalf("expected non-nil pointer")
+	}
+	if size != 4 {
+		t.Errorf("size = %d, want 4", size)
+	}
+	got := unsafe.Slice((*byte)(ptr), size)
+	for i, b := range value {
+		if got[i] != b {
+			t.Errorf("byte %d = %d, want %d", i, got[i], b)
+		}
+	}
+}
+
+// TestDynamicComponentsHaveDistinctBits tests that two DynamicComponents
+// registered separately don't collide on the same mask bit, even though
+// they share the same backing Go type
+func TestDynamicComponentsHaveDistinctBits(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	health, err := Factory.NewDynamicComponent("Health", 4, 4)
+	if err != nil {
+		t.Fatalf("NewDynamicComponent failed: %v", err)
+	}
+	mana, err := Factory.NewDynamicComponent("Mana", 4, 4)
+	if err != nil {
+		t.Fatalf("NewDynamicComponent failed: %v", err)
+	}
+
+	if storage.RowIndexFor(health) == storage.RowIndexFor(mana) {
+		t.Errorf("expected distinct mask bits for separately registered DynamicComponents")
+	}
+
+	arch, err := storage.NewOrExistingArchetype(health, mana)
+	if err != nil {
+		t.Fatalf("NewOrExistingArchetype failed: %v", err)
+	}
+	if err := arch.Generate(1,
+		DynamicComponentValue{Component: health, Value: []byte{9, 9, 9, 9}},
+		DynamicComponentValue{Component: mana, Value: []byte{5, 5, 5, 5}},
+	); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(health, mana), storage)
+	if !cursor.Next() {
+		t.Fatalf("expected one matching entity")
+	}
+	entity, err := cursor.CurrentEntity()
+	if err != nil {
+		t.Fatalf("CurrentEntity failed: %v", err)
+	}
+
+	healthPtr, _ := NewDynamicAccessor(health).GetFromEntity(entity)
+	manaPtr, _ := NewDynamicAccessor(mana).GetFromEntity(entity)
+	if *(*byte)(healthPtr) != 9 {
+		t.Errorf("health byte = %d, want 9", *(*byte)(healthPtr))
+	}
+	if *(*byte)(manaPtr) != 5 {
+		t.Errorf("mana byte = %d, want 5", *(*byte)(manaPtr))
+	}
+}
+
+// TestNewDynamicComponentRejectsOversizedValue tests that registering a
+// dynamic component larger than the backing byte array is rejected
+func TestNewDynamicComponentRejectsOversizedValue(t *testing.T) {
+	if _, err := Factory.NewDynamicComponent("TooBig", dynamicComponentMaxSize+1, 1); err == nil {
+		t.Errorf("expected an error for a size exceeding dynamicComponentMaxSize")
+	}
+}
@@ -25,12 +25,23 @@ type Entity interface {
 
 	AddComponent(Component) error
 	AddComponentWithValue(Component, any) error
+	SetComponent(Component, any) error
 	RemoveComponent(Component) error
 
 	EnqueueAddComponent(Component) error
 	EnqueueAddComponentWithValue(Component, any) error
 	EnqueueRemoveComponent(Component) error
 
+	// AddRelation records that this entity has a kind relation to target.
+	AddRelation(kind RelationKind, target Entity) error
+	// RemoveRelation removes a previously recorded kind relation to target.
+	RemoveRelation(kind RelationKind, target Entity) error
+	// EnqueueRemoveRelation queues the removal, or applies it immediately if
+	// storage isn't locked.
+	EnqueueRemoveRelation(kind RelationKind, target Entity) error
+	// Targets returns every entity this entity has a kind relation to.
+	Targets(kind RelationKind) []Entity
+
 	Components() []Component
 	ComponentsAsString() string
 
@@ -48,7 +59,18 @@ type entity struct {
 	id            table.EntryID
 	sto           Storage
 	relationships relationships
+	relations     map[reflect.Type][]Entity
 	components    []Component
+
+	// archetypeID and row record the entity's storage location as of its
+	// last move. NewEntities, TransferEntities, AddComponent, RemoveComponent,
+	// and Changeset.Apply set both. Fast-path accessors (see
+	// AccessibleComponent.GetFast, Cursor.GetByEntity) use archetypeID to go
+	// straight to the entity's archetype table. row is not refreshed when a
+	// sibling's destruction swap-removes this entity to a different row, so
+	// those accessors resolve the row live via Index() instead of reading it.
+	archetypeID archetypeID
+	row         uint32
 }
 
 // relationships tracks parent-child relationships and destroy callbacks
@@ -83,20 +105,23 @@ func (e *entity) Storage() Storage {
 	return e.sto
 }
 
-// SetParent establishes a parent-child relationship with another entity
+// SetParent establishes a parent-child relationship with another entity.
+// The relationship is also recorded as a parentRelationKind relation, so
+// hierarchy queries (HasRelation(parentRelationKind, parent),
+// Storage.Descendants) and DestroyEntities' despawn cascading see it too.
 func (e *entity) SetParent(parent Entity, callback EntityDestroyCallback) error {
 	if e.relationships.parent != nil {
 		return fmt.Errorf(
-			"entity already has parent", "attemped child", e, "attempted parent", parent, "existing parent", e.relationships.parent,
+			"entity already has parent: child %v, attempted parent %v, existing parent %v",
+			e, parent, e.relationships.parent,
 		)
 	}
-	e.relationships.parent = parent
-	e.relationships.recycled = parent.Recycled()
-	err := parent.SetDestroyCallback(callback)
-	if err != nil {
+	if err := e.AddRelation(parentRelationKind, parent); err != nil {
 		return err
 	}
-	return nil
+	e.relationships.parent = parent
+	e.relationships.recycled = parent.Recycled()
+	return parent.SetDestroyCallback(callback)
 }
 
 // Parent returns the parent entity if it exists and hasn't been recycled
@@ -121,6 +146,8 @@ func (e *entity) AddComponent(c Component) error {
 	if e.sto.Locked() {
 		return errors.New("storage is locked")
 	}
+	e.sto.beginTxBatch()
+	defer e.sto.endTxBatch()
 
 	originTable := e.Table()
 	if originTable.Contains(c) {
@@ -134,14 +161,24 @@ func (e *entity) AddComponent(c Component) error {
 		}
 	}
 
+	fromImpl, hasFromImpl := e.sto.archetypeFor(e.archetypeID)
+	var fromArchetype Archetype
+	if hasFromImpl {
+		fromArchetype = fromImpl
+	}
 	e.components = append(e.components, c)
-	destArchetype, err := e.sto.NewOrExistingArchetype(e.components...)
+	destArchetype, err := e.sto.archetypeViaAddEdge(fromImpl, hasFromImpl, c, e.components)
 	if err != nil {
 		return err
 	}
 	if err := originTable.TransferEntries(destArchetype.Table(), e.Index()); err != nil {
 		return err
 	}
+	e.archetypeID = archetypeID(destArchetype.ID())
+	e.row = uint32(e.Index())
+	e.stampAdded(destArchetype, c)
+	e.sto.recordTxMoved(e, fromArchetype, destArchetype)
+	e.sto.fireArchetypeTriggers(e, fromArchetype, destArchetype)
 	return nil
 }
 
@@ -150,6 +187,8 @@ func (e *entity) AddComponentWithValue(c Component, value any) error {
 	if e.sto.Locked() {
 		return errors.New("storage is locked")
 	}
+	e.sto.beginTxBatch()
+	defer e.sto.endTxBatch()
 
 	originTable := e.Table()
 	if originTable.Contains(c) {
@@ -163,34 +202,105 @@ func (e *entity) AddComponentWithValue(c Component, value any) error {
 		}
 	}
 
+	fromImpl, hasFromImpl := e.sto.archetypeFor(e.archetypeID)
+	var fromArchetype Archetype
+	if hasFromImpl {
+		fromArchetype = fromImpl
+	}
 	e.components = append(e.components, c)
-	destArchetype, err := e.sto.NewOrExistingArchetype(e.components...)
+	destArchetype, err := e.sto.archetypeViaAddEdge(fromImpl, hasFromImpl, c, e.components)
 	if err != nil {
 		return err
 	}
 	if err := originTable.TransferEntries(destArchetype.Table(), e.Index()); err != nil {
 		return err
 	}
+	e.archetypeID = archetypeID(destArchetype.ID())
+	e.row = uint32(e.Index())
+	e.stampAdded(destArchetype, c)
+	e.sto.recordTxMoved(e, fromArchetype, destArchetype)
+	e.sto.fireArchetypeTriggers(e, fromArchetype, destArchetype)
 
 	valueType := reflect.TypeOf(value)
 	for _, row := range destArchetype.Table().Rows() {
 		if row.Type().Elem() == valueType {
 			reflect.Value(row).Index(e.Index()).Set(reflect.ValueOf(value))
+			e.sto.recordTxChanged(e, c, nil, value, destArchetype)
+			spatialReindex(e.sto, c, e.id, value)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value type %v for component %v", valueType, c.Type())
+}
+
+// SetComponent overwrites an existing component's value and stamps it as
+// changed for this tick, so query.Changed(c) picks it up on the next pass
+func (e *entity) SetComponent(c Component, value any) error {
+	if e.sto.Locked() {
+		return errors.New("storage is locked")
+	}
+	e.sto.beginTxBatch()
+	defer e.sto.endTxBatch()
+
+	tbl := e.Table()
+	if !tbl.Contains(c) {
+		return ComponentNotFoundError{Component: c}
+	}
+	before := getRowValue(tbl, e.Index(), c)
+	valueType := reflect.TypeOf(value)
+	for _, row := range tbl.Rows() {
+		if row.Type().Elem() == valueType {
+			reflect.Value(row).Index(e.Index()).Set(reflect.ValueOf(value))
+			e.stampChanged(c)
+			if arch, ok := e.sto.archetypeFor(e.archetypeID); ok {
+				e.sto.recordTxChanged(e, c, before, value, arch)
+			}
+			spatialReindex(e.sto, c, e.id, value)
 			return nil
 		}
 	}
 	return fmt.Errorf("invalid value type %v for component %v", valueType, c.Type())
 }
 
+// stampAdded marks the component as both added and changed on the entity's
+// current row within destArchetype
+func (e *entity) stampAdded(destArchetype Archetype, c Component) {
+	arch, ok := asArchetypeImpl(destArchetype)
+	if !ok {
+		return
+	}
+	bit := e.sto.RowIndexFor(c)
+	arch.stampAdded(bit, e.Index(), e.sto.CurrentTick())
+}
+
+// stampChanged marks the component as changed on the entity's current row
+func (e *entity) stampChanged(c Component) {
+	arch, ok := e.sto.archetypeFor(e.archetypeID)
+	if !ok {
+		return
+	}
+	bit := e.sto.RowIndexFor(c)
+	arch.stampChanged(bit, e.Index(), e.sto.CurrentTick())
+}
+
 // RemoveComponent removes a component from the entity, moving it to a new archetype
 func (e *entity) RemoveComponent(c Component) error {
 	if e.sto.Locked() {
 		return errors.New("storage is locked")
 	}
+	e.sto.beginTxBatch()
+	defer e.sto.endTxBatch()
+
 	originTable := e.Table()
 	if !originTable.Contains(c) {
 		return nil
 	}
+	before := getRowValue(originTable, e.Index(), c)
+	fromImpl, hasFromImpl := e.sto.archetypeFor(e.archetypeID)
+	var fromArchetype Archetype
+	if hasFromImpl {
+		fromArchetype = fromImpl
+	}
 	newComps := []Component{}
 	for _, comp := range e.components {
 		if comp.ID() != c.ID() {
@@ -198,16 +308,82 @@ func (e *entity) RemoveComponent(c Component) error {
 		}
 	}
 	e.components = newComps
-	destArchetype, err := e.sto.NewOrExistingArchetype(newComps...)
+	destArchetype, err := e.sto.archetypeViaRemoveEdge(fromImpl, hasFromImpl, c, newComps)
 	if err != nil {
 		return fmt.Errorf("failed to get/create archetype: %w", err)
 	}
 	if err := originTable.TransferEntries(destArchetype.Table(), e.Index()); err != nil {
 		return fmt.Errorf("failed to transfer entity: %w", err)
 	}
+	e.sto.recordRemoval(e.sto.RowIndexFor(c), e.id)
+	e.archetypeID = archetypeID(destArchetype.ID())
+	e.row = uint32(e.Index())
+	e.sto.recordTxMoved(e, fromArchetype, destArchetype)
+	e.sto.fireArchetypeTriggers(e, fromArchetype, destArchetype)
+	e.sto.recordTxChanged(e, c, before, nil, fromArchetype)
+	spatialRemove(e.sto, c, e.id)
 	return nil
 }
 
+// AddRelation records that this entity has a kind relation to target. A
+// duplicate (kind, target) pair is a no-op.
+func (e *entity) AddRelation(kind RelationKind, target Entity) error {
+	if e.sto.Locked() {
+		return errors.New("storage is locked")
+	}
+	if e.relations == nil {
+		e.relations = make(map[reflect.Type][]Entity)
+	}
+	bit := kind.id()
+	for _, t := range e.relations[bit] {
+		if t.ID() == target.ID() {
+			return nil
+		}
+	}
+	e.relations[bit] = append(e.relations[bit], target)
+	e.sto.trackRelationHolder(kind, target, e)
+	return nil
+}
+
+// RemoveRelation removes a previously recorded kind relation to target, if
+// present
+func (e *entity) RemoveRelation(kind RelationKind, target Entity) error {
+	if e.sto.Locked() {
+		return errors.New("storage is locked")
+	}
+	bit := kind.id()
+	targets := e.relations[bit]
+	for i, t := range targets {
+		if t.ID() == target.ID() {
+			e.relations[bit] = append(targets[:i], targets[i+1:]...)
+			e.sto.untrackRelationHolder(kind, target, e)
+			return nil
+		}
+	}
+	return nil
+}
+
+// EnqueueRemoveRelation queues the relation removal, or executes it
+// immediately if storage isn't locked
+func (e *entity) EnqueueRemoveRelation(kind RelationKind, target Entity) error {
+	if !e.sto.Locked() {
+		return e.RemoveRelation(kind, target)
+	}
+	e.sto.Enqueue(RemoveRelationOperation{
+		entity:   e,
+		recycled: e.Recycled(),
+		kind:     kind,
+		target:   target,
+		storage:  e.sto,
+	})
+	return nil
+}
+
+// Targets returns every entity this entity has a kind relation to
+func (e *entity) Targets(kind RelationKind) []Entity {
+	return e.relations[kind.id()]
+}
+
 // EnqueueAddComponent queues a component addition or executes immediately if storage isn't locked
 func (e *entity) EnqueueAddComponent(c Component) error {
 	if !e.sto.Locked() {
@@ -152,6 +152,129 @@ func TestComponentAddRemove(t *testing.T) {
 	}
 }
 
+// TestArchetypeMoveEdgesAreCached tests that repeated AddComponent/
+// RemoveComponent calls for the same component, from the same origin
+// archetype, reuse a cached edge instead of creating a redundant archetype
+// on every transition
+func TestArchetypeMoveEdgesAreCached(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(5, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	for _, en := range entities {
+		if err := en.AddComponent(velComp); err != nil {
+			t.Fatalf("AddComponent() error = %v", err)
+		}
+	}
+	// One archetype for {Position}, one for {Position, Velocity} -- the
+	// cached add edge should prevent a third from being created.
+	if got := len(storage.Archetypes()); got != 2 {
+		t.Errorf("Archetypes() = %d after repeated AddComponent, want 2", got)
+	}
+
+	for _, en := range entities {
+		if err := en.RemoveComponent(velComp); err != nil {
+			t.Fatalf("RemoveComponent() error = %v", err)
+		}
+		if len(en.Components()) != 1 {
+			t.Errorf("Entity has %d components, want 1", len(en.Components()))
+		}
+	}
+	// The cached remove edge should route every entity straight back to the
+	// existing {Position} archetype rather than minting a new one.
+	if got := len(storage.Archetypes()); got != 2 {
+		t.Errorf("Archetypes() = %d after repeated RemoveComponent, want 2", got)
+	}
+}
+
+func TestCachedLocationFastPath(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	entity := entities[0]
+
+	pos := Position{X: 1.0, Y: 2.0}
+	*posComp.GetFromEntity(entity) = pos
+
+	if got := posComp.GetFast(entity); got.X != pos.X || got.Y != pos.Y {
+		t.Errorf("GetFast() = %+v, want %+v", got, pos)
+	}
+
+	// Adding a component moves the entity to a new archetype/row; the
+	// cached location must follow it.
+	vel := Velocity{X: 3.0, Y: 4.0}
+	if err := entity.AddComponentWithValue(velComp, vel); err != nil {
+		t.Fatalf("Failed to add velocity: %v", err)
+	}
+
+	if got := posComp.GetFast(entity); got.X != pos.X || got.Y != pos.Y {
+		t.Errorf("GetFast() after AddComponent = %+v, want %+v", got, pos)
+	}
+	if got := velComp.GetFast(entity); got.X != vel.X || got.Y != vel.Y {
+		t.Errorf("GetFast() for velocity = %+v, want %+v", got, vel)
+	}
+
+	// Transferring to another storage must also refresh the cached location.
+	schema2 := table.Factory.NewSchema()
+	storage2 := Factory.NewStorage(schema2)
+	if err := storage.TransferEntities(storage2, entity); err != nil {
+		t.Fatalf("Failed to transfer entity: %v", err)
+	}
+	if got := posComp.GetFast(entity); got.X != pos.X || got.Y != pos.Y {
+		t.Errorf("GetFast() after transfer = %+v, want %+v", got, pos)
+	}
+}
+
+// TestGetFastAfterSwapRemove tests that GetFast/GetByEntity stay correct for
+// a surviving entity after a sibling in the same archetype is destroyed,
+// which swap-removes the tail row into the deleted slot and shifts the
+// survivor's row without refreshing any cached location on it.
+func TestGetFastAfterSwapRemove(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	entities, err := storage.NewEntities(3, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	tail := entities[2]
+	*posComp.GetFromEntity(tail) = Position{X: 9.0, Y: 9.0}
+
+	if err := storage.DestroyEntities(entities[1]); err != nil {
+		t.Fatalf("Failed to destroy entity: %v", err)
+	}
+
+	want := posComp.GetFromEntity(tail)
+	got := posComp.GetFast(tail)
+	if got.X != want.X || got.Y != want.Y {
+		t.Errorf("GetFast() after swap-remove = %+v, want %+v", got, want)
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	tbl, row, ok := cursor.GetByEntity(tail)
+	if !ok {
+		t.Fatalf("GetByEntity() returned ok = false")
+	}
+	if row != tail.Index() {
+		t.Errorf("GetByEntity() row = %d, want %d", row, tail.Index())
+	}
+	_ = tbl
+}
+
 func TestComponentValues(t *testing.T) {
 	schema := table.Factory.NewSchema()
 	storage := Factory.NewStorage(schema)
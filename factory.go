@@ -23,19 +23,43 @@ func (f factory) NewCursor(query QueryNode, storage Storage) *Cursor {
 	return newCursor(query, storage)
 }
 
+// NewWorld creates an empty World for aggregating multiple storages.
+func (f factory) NewWorld() *World {
+	return &World{}
+}
+
 // FactoryNewComponent creates a new AccessibleComponent for type T.
 func FactoryNewComponent[T any]() AccessibleComponent[T] {
 	iden := table.FactoryNewElementType[T]()
-	return AccessibleComponent[T]{
+	c := AccessibleComponent[T]{
 		Component: iden,
 		Accessor:  table.FactoryNewAccessor[T](iden),
 	}
+	registerComponentType(c.Component)
+	return c
 }
 
-// FactoryNewCache creates a new Cache with the specified capacity.
+// FactoryNewCache creates a new Cache with the specified capacity. Once
+// full, Register returns an error.
 func FactoryNewCache[T any](cap int) Cache[T] {
 	return &SimpleCache[T]{
 		itemIndices: make(map[string]int),
+		keysByIndex: make(map[int]string),
 		maxCapacity: cap,
 	}
 }
+
+// FactoryNewLFUCache creates a new LFUCache with the specified capacity
+// that, once full, evicts its least-frequently-accessed item to make room
+// for new registrations instead of returning an error, breaking ties
+// between equally-frequent items by least-recently-used.
+func FactoryNewLFUCache[T any](cap int) Cache[T] {
+	return &LFUCache[T]{
+		SimpleCache[T]{
+			itemIndices:   make(map[string]int),
+			keysByIndex:   make(map[int]string),
+			maxCapacity:   cap,
+			lfuEvictionOn: true,
+		},
+	}
+}
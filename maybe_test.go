@@ -0,0 +1,89 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestMaybeDoesNotConstrainMatching tests that a query built with Maybe
+// still matches archetypes lacking the optional component
+func TestMaybeDoesNotConstrainMatching(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	if _, err := storage.NewEntities(2, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if _, err := storage.NewEntities(3, posComp, velComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	query := Factory.NewQuery().And(posComp, Maybe[Velocity]())
+	cursor := Factory.NewCursor(query, storage)
+	if total := cursor.TotalMatched(); total != 5 {
+		t.Errorf("TotalMatched() = %d, want 5", total)
+	}
+}
+
+// TestOptionalFromCursor tests that OptionalFromCursor reports presence per
+// archetype and returns the component's value when present
+func TestOptionalFromCursor(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	withoutVel, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if err := withoutVel[0].SetComponent(posComp, Position{X: 1}); err != nil {
+		t.Fatalf("Failed to set position: %v", err)
+	}
+
+	withVel, err := storage.NewEntities(1, posComp, velComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if err := withVel[0].SetComponent(velComp, Velocity{X: 9}); err != nil {
+		t.Fatalf("Failed to set velocity: %v", err)
+	}
+
+	query := Factory.NewQuery().And(posComp, Maybe[Velocity]())
+	cursor := Factory.NewCursor(query, storage)
+
+	seenWithVel, seenWithoutVel := 0, 0
+	for cursor.Next() {
+		vel, ok := velComp.OptionalFromCursor(cursor)
+		if ok {
+			seenWithVel++
+			if vel.X != 9 {
+				t.Errorf("OptionalFromCursor value X = %v, want 9", vel.X)
+			}
+		} else {
+			seenWithoutVel++
+			if vel != nil {
+				t.Errorf("OptionalFromCursor value = %v, want nil when not present", vel)
+			}
+		}
+	}
+	if seenWithVel != 1 || seenWithoutVel != 1 {
+		t.Errorf("OptionalFromCursor presence = (%d, %d), want (1, 1)", seenWithVel, seenWithoutVel)
+	}
+}
+
+// TestMaybePanicsForUnregisteredType tests that Maybe panics when T has no
+// component constructed for it in this process
+func TestMaybePanicsForUnregisteredType(t *testing.T) {
+	type Unregistered struct{ Value int }
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Maybe[Unregistered]() did not panic")
+		}
+	}()
+	Maybe[Unregistered]()
+}
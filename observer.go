@@ -0,0 +1,208 @@
+package warehouse
+
+// observerDispatchLockBit is the reserved storage lock bit held while
+// observer callbacks run, in the style of chunkIterationLockBit and
+// snapshotLockBit. Holding it means a callback's own mutations go through
+// Enqueue* and land in the queue instead of applying immediately; releasing
+// the lock drains that queue as a follow-up batch, which dispatches its own
+// report in turn.
+const observerDispatchLockBit uint32 = 29
+
+// TxReport describes every entity creation, destruction, archetype move,
+// and component value change observed during one batch of mutation: either
+// a single direct call (AddComponent, DestroyEntities, ...) or everything
+// applied while draining a storage's operation queue once its last lock is
+// released. Changeset.Apply does not yet emit TxReport events; it's a
+// separate, as-yet-unobserved mutation path.
+type TxReport struct {
+	Created   []Entity
+	Destroyed []Entity
+	Moved     []EntityMove
+	Changed   []ComponentChange
+}
+
+// EntityMove records an entity's transition from one archetype to another.
+// From is nil when the entity's previous archetype couldn't be resolved
+// from its cached location. Creations are not reported as an EntityMove;
+// see TxReport.Created.
+type EntityMove struct {
+	Entity Entity
+	From   Archetype
+	To     Archetype
+}
+
+// ComponentChange records a component value overwrite. Before is nil when
+// the value was just added (AddComponentWithValue); After is nil when the
+// component was removed and its prior value could be read.
+type ComponentChange struct {
+	Entity    Entity
+	Component Component
+	Before    any
+	After     any
+}
+
+// empty reports whether a report carries no events at all
+func (r TxReport) empty() bool {
+	return len(r.Created) == 0 && len(r.Destroyed) == 0 && len(r.Moved) == 0 && len(r.Changed) == 0
+}
+
+// txKind identifies what a txEvent records
+type txKind int
+
+const (
+	txCreated txKind = iota
+	txDestroyed
+	txMoved
+	txChanged
+)
+
+// txEvent is the internal record of a single mutation, accumulated on
+// storage between batch boundaries and turned into a TxReport (filtered
+// per observer) once the batch ends
+type txEvent struct {
+	kind      txKind
+	entity    Entity
+	from, to  Archetype
+	component Component
+	before    any
+	after     any
+}
+
+// txObserver pairs a registered callback with the filter that decides
+// which events it should see
+type txObserver struct {
+	name   string
+	filter QueryNode
+	cb     func(TxReport)
+}
+
+// AddObserver registers cb to be called with a TxReport after each batch of
+// mutations containing at least one event matching filter, in the style of
+// Mentat's tx_observer. filter is evaluated against both an event's
+// before-mutation and after-mutation archetype, so an observer watching
+// And(posComp, velComp) fires both when an entity enters and leaves that
+// set. Pass a nil filter to observe every mutation. Registering again under
+// the same name replaces the previous observer.
+func (s *storage) AddObserver(name string, filter QueryNode, cb func(TxReport)) {
+	s.RemoveObserver(name)
+	s.observers = append(s.observers, txObserver{name: name, filter: filter, cb: cb})
+}
+
+// RemoveObserver unregisters the observer previously added under name, if any
+func (s *storage) RemoveObserver(name string) {
+	for i, obs := range s.observers {
+		if obs.name == name {
+			s.observers = append(s.observers[:i], s.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+// recordTxCreated records en's creation into archetype to
+func (s *storage) recordTxCreated(en Entity, to Archetype) {
+	if len(s.observers) == 0 {
+		return
+	}
+	s.pendingTxEvents = append(s.pendingTxEvents, txEvent{kind: txCreated, entity: en, to: to})
+}
+
+// recordTxDestroyed records en's destruction out of archetype from
+func (s *storage) recordTxDestroyed(en Entity, from Archetype) {
+	if len(s.observers) == 0 {
+		return
+	}
+	s.pendingTxEvents = append(s.pendingTxEvents, txEvent{kind: txDestroyed, entity: en, from: from})
+}
+
+// recordTxMoved records en's transition from one archetype to another
+func (s *storage) recordTxMoved(en Entity, from, to Archetype) {
+	if len(s.observers) == 0 {
+		return
+	}
+	s.pendingTxEvents = append(s.pendingTxEvents, txEvent{kind: txMoved, entity: en, from: from, to: to})
+}
+
+// recordTxChanged records a component value overwrite on en, still within
+// archetype arch
+func (s *storage) recordTxChanged(en Entity, c Component, before, after any, arch Archetype) {
+	if len(s.observers) == 0 {
+		return
+	}
+	s.pendingTxEvents = append(s.pendingTxEvents, txEvent{
+		kind: txChanged, entity: en, component: c, before: before, after: after, from: arch, to: arch,
+	})
+}
+
+// beginTxBatch marks the start of a unit of mutation that should coalesce
+// into a single TxReport per observer: either a single direct call, or one
+// operation-queue drain. Calls nest: a drain that applies several
+// operations, each of which calls beginTxBatch/endTxBatch itself, only
+// dispatches once, when the outermost call ends.
+func (s *storage) beginTxBatch() {
+	s.txDepth++
+}
+
+// endTxBatch closes a unit started by beginTxBatch, dispatching to
+// observers once nesting unwinds back to zero
+func (s *storage) endTxBatch() {
+	s.txDepth--
+	if s.txDepth > 0 {
+		return
+	}
+	if len(s.pendingTxEvents) == 0 {
+		return
+	}
+	s.dispatchObservers()
+}
+
+// dispatchObservers builds a TxReport per observer from the events
+// recorded since the last dispatch, filtered to what each observer's
+// filter matches, and delivers it if non-empty. Callbacks run with the
+// storage locked under observerDispatchLockBit, so mutations made from
+// inside a callback enqueue rather than apply immediately.
+func (s *storage) dispatchObservers() {
+	events := s.pendingTxEvents
+	s.pendingTxEvents = nil
+
+	s.AddLock(observerDispatchLockBit)
+	defer s.RemoveLock(observerDispatchLockBit)
+
+	for _, obs := range s.observers {
+		var report TxReport
+		for _, ev := range events {
+			if !matchesObserverFilter(obs.filter, ev, s) {
+				continue
+			}
+			switch ev.kind {
+			case txCreated:
+				report.Created = append(report.Created, ev.entity)
+			case txDestroyed:
+				report.Destroyed = append(report.Destroyed, ev.entity)
+			case txMoved:
+				report.Moved = append(report.Moved, EntityMove{Entity: ev.entity, From: ev.from, To: ev.to})
+			case txChanged:
+				report.Changed = append(report.Changed, ComponentChange{
+					Entity: ev.entity, Component: ev.component, Before: ev.before, After: ev.after,
+				})
+			}
+		}
+		if !report.empty() {
+			obs.cb(report)
+		}
+	}
+}
+
+// matchesObserverFilter reports whether ev's pre- or post-mutation
+// archetype satisfies filter. A nil filter matches every event.
+func matchesObserverFilter(filter QueryNode, ev txEvent, storage Storage) bool {
+	if filter == nil {
+		return true
+	}
+	if ev.from != nil && filter.Evaluate(ev.from, storage) {
+		return true
+	}
+	if ev.to != nil && filter.Evaluate(ev.to, storage) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestObserverFiresOnCreateAndDestroy tests that a direct, unlocked
+// mutation dispatches its own TxReport immediately
+func TestObserverFiresOnCreateAndDestroy(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	var reports []TxReport
+	storage.AddObserver("watch-pos", Factory.NewQuery().And(posComp), func(r TxReport) {
+		reports = append(reports, r)
+	})
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if len(reports) != 1 || len(reports[0].Created) != 1 {
+		t.Fatalf("expected 1 report with 1 created entity, got %+v", reports)
+	}
+
+	if err := storage.DestroyEntities(entities[0]); err != nil {
+		t.Fatalf("DestroyEntities failed: %v", err)
+	}
+	if len(reports) != 2 || len(reports[1].Destroyed) != 1 {
+		t.Fatalf("expected a second report with 1 destroyed entity, got %+v", reports)
+	}
+}
+
+// TestObserverCoalescesQueuedOperations tests that every operation applied
+// while draining a single RemoveLock-triggered queue produces one report
+func TestObserverCoalescesQueuedOperations(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(2, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	var reports []TxReport
+	storage.AddObserver("watch-vel", Factory.NewQuery().And(velComp), func(r TxReport) {
+		reports = append(reports, r)
+	})
+
+	storage.AddLock(1)
+	for _, en := range entities {
+		if err := en.EnqueueAddComponent(velComp); err != nil {
+			t.Fatalf("EnqueueAddComponent failed: %v", err)
+		}
+	}
+	storage.RemoveLock(1)
+
+	if len(reports) != 1 {
+		t.Fatalf("expected exactly 1 coalesced report, got %d", len(reports))
+	}
+	if len(reports[0].Moved) != 2 {
+		t.Errorf("expected 2 moves in the coalesced report, got %d", len(reports[0].Moved))
+	}
+}
+
+// TestObserverFiltersOnEnterAndLeave tests that a filter fires for both a
+// component addition (entering the set) and a removal (leaving the set)
+func TestObserverFiltersOnEnterAndLeave(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	entity := entities[0]
+
+	var fired int
+	storage.AddObserver("watch-both", Factory.NewQuery().And(posComp, velComp), func(r TxReport) {
+		fired++
+	})
+
+	if err := entity.AddComponent(velComp); err != nil {
+		t.Fatalf("AddComponent failed: %v", err)
+	}
+	if err := entity.RemoveComponent(velComp); err != nil {
+		t.Fatalf("RemoveComponent failed: %v", err)
+	}
+
+	if fired != 2 {
+		t.Errorf("expected observer to fire on both entering and leaving the set, fired %d times", fired)
+	}
+}
+
+// TestObserverRemove tests that RemoveObserver stops delivery
+func TestObserverRemove(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	fired := 0
+	storage.AddObserver("temp", nil, func(r TxReport) { fired++ })
+	storage.RemoveObserver("temp")
+
+	if _, err := storage.NewEntities(1, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if fired != 0 {
+		t.Errorf("expected removed observer not to fire, fired %d times", fired)
+	}
+}
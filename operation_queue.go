@@ -1,5 +1,7 @@
 package warehouse
 
+import "github.com/TheBitDrifter/table"
+
 // EntityOperation represents an operation that can be applied to a storage
 type EntityOperation interface {
 	Apply(Storage) error
@@ -23,6 +25,9 @@ func (queue *entityOperationsQueue) ProcessAll(sto Storage) error {
 	if sto.Locked() {
 		return nil // Return without error, but don't clear queue
 	}
+	sto.beginTxBatch()
+	defer sto.endTxBatch()
+
 	for _, op := range queue.operations {
 		err := op.Apply(sto)
 		if err != nil {
@@ -159,3 +164,135 @@ func (op RemoveComponentOperation) Apply(sto Storage) error {
 	}
 	return nil
 }
+
+// SetComponentOperation overwrites an entity's component value
+type SetComponentOperation struct {
+	entity    Entity
+	recycled  int
+	component Component
+	value     any
+	storage   Storage
+}
+
+// Apply overwrites the component value if conditions are met
+func (op SetComponentOperation) Apply(sto Storage) error {
+	if !op.entity.Valid() {
+		return nil
+	}
+	if op.entity.Recycled() != op.recycled {
+		return nil
+	}
+	if op.storage != op.entity.Storage() {
+		return nil
+	}
+	return op.entity.SetComponent(op.component, op.value)
+}
+
+// RemoveRelationOperation removes a kind relation from an entity to target
+type RemoveRelationOperation struct {
+	entity   Entity
+	recycled int
+	kind     RelationKind
+	target   Entity
+	storage  Storage
+}
+
+// Apply removes the relation if conditions are met
+func (op RemoveRelationOperation) Apply(sto Storage) error {
+	if !op.entity.Valid() {
+		return nil
+	}
+	if op.entity.Recycled() != op.recycled {
+		return nil
+	}
+	if op.storage != op.entity.Storage() {
+		return nil
+	}
+	return op.entity.RemoveRelation(op.kind, op.target)
+}
+
+// BatchAddComponentOperation adds a single component to many entities.
+// Entities are grouped by their origin table and then moved one at a time
+// by AddComponent, so each entity gets its own archetype move; after the
+// first move out of a given origin archetype, the destination comes from
+// that archetype's cached add edge.
+type BatchAddComponentOperation struct {
+	entities  []Entity
+	component Component
+}
+
+// Apply adds the component to every still-valid entity in the batch
+func (op BatchAddComponentOperation) Apply(sto Storage) error {
+	groups := make(map[table.Table][]Entity)
+	for _, en := range op.entities {
+		if en == nil || !en.Valid() {
+			continue
+		}
+		groups[en.Table()] = append(groups[en.Table()], en)
+	}
+	for _, group := range groups {
+		for _, en := range group {
+			if err := en.AddComponent(op.component); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CommandBuffer is a fluent builder over a storage's deferred operation
+// queue, returned by Storage.Commands(), so callers can record operations
+// without constructing operation structs directly.
+type CommandBuffer struct {
+	storage Storage
+}
+
+// NewEntities records a new-entities operation
+func (cb *CommandBuffer) NewEntities(n int, components ...Component) *CommandBuffer {
+	cb.storage.EnqueueNewEntities(n, components...)
+	return cb
+}
+
+// DestroyEntity records an entity-destruction operation
+func (cb *CommandBuffer) DestroyEntity(e Entity) *CommandBuffer {
+	cb.storage.EnqueueDestroyEntities(e)
+	return cb
+}
+
+// AddComponent records a component-addition operation
+func (cb *CommandBuffer) AddComponent(e Entity, c Component) *CommandBuffer {
+	e.EnqueueAddComponent(c)
+	return cb
+}
+
+// AddComponentWithValue records a component-addition operation with an
+// initial value
+func (cb *CommandBuffer) AddComponentWithValue(e Entity, c Component, value any) *CommandBuffer {
+	e.EnqueueAddComponentWithValue(c, value)
+	return cb
+}
+
+// RemoveComponent records a component-removal operation
+func (cb *CommandBuffer) RemoveComponent(e Entity, c Component) *CommandBuffer {
+	e.EnqueueRemoveComponent(c)
+	return cb
+}
+
+// SetComponent records a component-value overwrite
+func (cb *CommandBuffer) SetComponent(e Entity, c Component, value any) *CommandBuffer {
+	cb.storage.EnqueueSetComponent(e, c, value)
+	return cb
+}
+
+// Transfer records a cross-storage entity transfer
+func (cb *CommandBuffer) Transfer(target Storage, e Entity) *CommandBuffer {
+	cb.storage.EnqueueTransfer(target, e)
+	return cb
+}
+
+// BatchAddComponent records a single component addition for many entities
+// at once, applied as one BatchAddComponentOperation
+func (cb *CommandBuffer) BatchAddComponent(entities []Entity, c Component) *CommandBuffer {
+	cb.storage.Enqueue(BatchAddComponentOperation{entities: entities, component: c})
+	return cb
+}
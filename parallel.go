@@ -0,0 +1,191 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ParallelOptions configures Storage.RunParallel.
+type ParallelOptions struct {
+	// Workers is how many goroutines pull chunks concurrently. Zero or
+	// negative defaults to runtime.GOMAXPROCS(0).
+	Workers int
+	// ChunkSize is the maximum number of rows per ChunkView handed to a
+	// worker. Zero or negative hands each worker one whole archetype at a
+	// time, the same chunking Cursor.Chunks uses.
+	ChunkSize int
+}
+
+// RunParallel matches query and fans its ChunkViews out across a pool of
+// workers, each pulling its next chunk from a shared channel as it
+// finishes the last one — the same load-balancing a work-stealing deque
+// gives an uneven set of archetype sizes, without requiring a lock-free
+// deque of our own. Workers defaults to runtime.GOMAXPROCS(0); see
+// ParallelOptions for tuning.
+//
+// While the run is active the storage is held locked under
+// chunkIterationLockBit (the same bit Cursor.ForEachChunkParallel uses),
+// so component mutations attempted from inside fn must go through the
+// Enqueue* path and only apply once RunParallel returns. fn itself runs
+// concurrently across workers: when ChunkSize splits an archetype into
+// more than one chunk, two calls to fn can receive chunks from the same
+// archetype at once, so writing to the same component column across
+// chunks of one archetype is a data race unless the caller partitions
+// work by archetype (the default, when ChunkSize is zero) or synchronizes
+// with its own atomics.
+func (s *storage) RunParallel(query QueryNode, fn func(ChunkView), opts ParallelOptions) {
+	s.AddLock(chunkIterationLockBit)
+	defer s.RemoveLock(chunkIterationLockBit)
+
+	cursor := newCursor(query, s)
+	var chunks []ChunkView
+	for chunk := range cursor.ParallelChunks(opts.ChunkSize) {
+		chunks = append(chunks, chunk)
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+	if workers <= 1 {
+		for _, chunk := range chunks {
+			fn(chunk)
+		}
+		return
+	}
+
+	work := make(chan ChunkView)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for chunk := range work {
+				fn(chunk)
+			}
+		}()
+	}
+	for _, chunk := range chunks {
+		work <- chunk
+	}
+	close(work)
+	wg.Wait()
+}
+
+// CursorBatch is the unit of work Cursor.ParallelEach dispatches to a
+// worker: a single archetype's table plus a start/len bound within it, the
+// same shape Cursor.Chunks/ParallelChunks yield as a ChunkView.
+type CursorBatch = ChunkView
+
+// ParallelQueryConfig tunes Cursor.ParallelEach.
+type ParallelQueryConfig struct {
+	// Workers is how many goroutines pull batches concurrently. Zero or
+	// negative defaults to runtime.GOMAXPROCS(0).
+	Workers int
+	// MinChunkSize is the smallest archetype table length ParallelEach will
+	// still split into multiple batches of chunkSize rows; archetypes at or
+	// below it are dispatched as a single batch regardless of chunkSize,
+	// since splitting them further would add dispatch overhead without
+	// meaningfully reducing the work handed to any one goroutine.
+	MinChunkSize int
+	// Deterministic runs every batch in matched-archetype, start-offset
+	// order on the calling goroutine instead of a worker pool, for tests
+	// that assert on call order or that can't tolerate concurrent fn calls.
+	Deterministic bool
+}
+
+// ParallelEach partitions a query's matched archetypes (and any archetype
+// table larger than cfg.MinChunkSize, into batches of at most chunkSize
+// rows) into CursorBatches and dispatches them to a worker pool, the same
+// per-archetype/per-table dense iteration model Storage.RunParallel uses.
+//
+// Unlike RunParallel, ParallelEach doesn't lock the storage itself: the
+// caller must already hold it locked (e.g. via AddLock), since ctx may be
+// cancelled mid-dispatch and ParallelEach has no safe moment of its own to
+// release a lock it didn't take. ParallelEach returns an error if storage
+// isn't locked, and ctx.Err() if ctx is cancelled before every batch has
+// been dispatched.
+func (c *Cursor) ParallelEach(ctx context.Context, chunkSize int, fn func(batch CursorBatch), cfg ParallelQueryConfig) error {
+	if !c.storage.Locked() {
+		return fmt.Errorf("warehouse: ParallelEach requires the storage to be locked first (see Storage.AddLock)")
+	}
+
+	c.Initialize()
+	var batches []CursorBatch
+	for _, arch := range c.matchedStorages {
+		total := arch.table.Length()
+		size := chunkSize
+		if size <= 0 || total <= cfg.MinChunkSize {
+			size = total
+		}
+		for start := 0; start < total; start += size {
+			length := size
+			if start+length > total {
+				length = total - start
+			}
+			batches = append(batches, CursorBatch{archetype: arch, table: arch.table, start: start, length: length})
+		}
+	}
+	c.Reset()
+
+	if cfg.Deterministic {
+		for _, batch := range batches {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fn(batch)
+		}
+		return nil
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(batches) {
+		workers = len(batches)
+	}
+	if workers <= 1 {
+		for _, batch := range batches {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fn(batch)
+		}
+		return nil
+	}
+
+	work := make(chan CursorBatch)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for batch := range work {
+				select {
+				case <-ctx.Done():
+					continue
+				default:
+				}
+				fn(batch)
+			}
+		}()
+	}
+dispatch:
+	for _, batch := range batches {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case work <- batch:
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return ctx.Err()
+}
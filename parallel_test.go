@@ -0,0 +1,200 @@
+package warehouse
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestCursorParallelChunks tests that ParallelChunks splits an archetype
+// into sub-range chunks that together cover every row exactly once
+func TestCursorParallelChunks(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(5, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	var starts, lengths []int
+	for view := range cursor.ParallelChunks(2) {
+		starts = append(starts, view.Start())
+		lengths = append(lengths, view.Len())
+	}
+
+	if want := []int{0, 2, 4}; !equalInts(starts, want) {
+		t.Errorf("expected chunk starts %v, got %v", want, starts)
+	}
+	if want := []int{2, 2, 1}; !equalInts(lengths, want) {
+		t.Errorf("expected chunk lengths %v, got %v", want, lengths)
+	}
+}
+
+// TestAccessibleComponentSliceFromChunk tests that SliceFromChunk returns
+// just the rows belonging to a sub-range ChunkView
+func TestAccessibleComponentSliceFromChunk(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	entities, err := storage.NewEntities(4, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	for i, en := range entities {
+		*posComp.GetFromEntity(en) = Position{X: float64(i)}
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	var got []float64
+	for view := range cursor.ParallelChunks(3) {
+		for _, p := range posComp.SliceFromChunk(view) {
+			got = append(got, p.X)
+		}
+	}
+
+	want := []float64{0, 1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestStorageRunParallel tests that RunParallel visits every matched row
+// across however many workers it's given
+func TestStorageRunParallel(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(20, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	var mu sync.Mutex
+	seen := 0
+	storage.RunParallel(Factory.NewQuery().And(posComp), func(view ChunkView) {
+		mu.Lock()
+		seen += view.Len()
+		mu.Unlock()
+	}, ParallelOptions{Workers: 4, ChunkSize: 3})
+
+	if seen != 20 {
+		t.Errorf("expected RunParallel to visit 20 rows, visited %d", seen)
+	}
+}
+
+// TestStorageRunParallelLocksDuringRun tests that mutations attempted from
+// inside a RunParallel callback are deferred rather than applied
+func TestStorageRunParallelLocksDuringRun(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	entity := entities[0]
+
+	storage.RunParallel(Factory.NewQuery().And(posComp), func(view ChunkView) {
+		if err := entity.EnqueueAddComponent(velComp); err != nil {
+			t.Errorf("EnqueueAddComponent failed: %v", err)
+		}
+	}, ParallelOptions{})
+
+	if !velComp.Accessor.Check(entity.Table()) {
+		t.Errorf("expected queued AddComponent to apply once RunParallel released its lock")
+	}
+}
+
+// TestCursorParallelEachRequiresLock tests that ParallelEach refuses to run
+// if the caller hasn't already locked the storage
+func TestCursorParallelEachRequiresLock(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(3, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	err := cursor.ParallelEach(context.Background(), 0, func(batch CursorBatch) {}, ParallelQueryConfig{})
+	if err == nil {
+		t.Errorf("expected ParallelEach to error when storage isn't locked")
+	}
+}
+
+// TestCursorParallelEachDeterministic tests that Deterministic visits every
+// batch in matched-archetype, start-offset order on the calling goroutine
+func TestCursorParallelEachDeterministic(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(5, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	storage.AddLock(chunkIterationLockBit)
+	defer storage.RemoveLock(chunkIterationLockBit)
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	var starts []int
+	err := cursor.ParallelEach(context.Background(), 2, func(batch CursorBatch) {
+		starts = append(starts, batch.Start())
+	}, ParallelQueryConfig{Deterministic: true})
+	if err != nil {
+		t.Fatalf("ParallelEach returned error: %v", err)
+	}
+
+	if want := []int{0, 2, 4}; !equalInts(starts, want) {
+		t.Errorf("expected batch starts %v, got %v", want, starts)
+	}
+}
+
+// TestCursorParallelEachCancelled tests that ParallelEach stops dispatching
+// and returns ctx.Err() once ctx is cancelled
+func TestCursorParallelEachCancelled(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(5, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	storage.AddLock(chunkIterationLockBit)
+	defer storage.RemoveLock(chunkIterationLockBit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	err := cursor.ParallelEach(ctx, 1, func(batch CursorBatch) {}, ParallelQueryConfig{Deterministic: true})
+	if err == nil {
+		t.Errorf("expected ParallelEach to return ctx.Err() once cancelled")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package warehouse
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/TheBitDrifter/bark"
 	"github.com/TheBitDrifter/mask"
@@ -14,6 +15,38 @@ type Query interface {
 	And(items ...interface{}) QueryNode
 	Or(items ...interface{}) QueryNode
 	Not(items ...interface{}) QueryNode
+
+	// Added matches entities whose component was added since the cursor
+	// last observed this query.
+	Added(component Component) QueryNode
+	// Changed matches entities whose component was added or written since
+	// the cursor last observed this query.
+	Changed(component Component) QueryNode
+	// Removed matches entities that had this component removed since the
+	// cursor last observed this query. Unlike Added/Changed, the matching
+	// entities no longer carry the component.
+	Removed(component Component) QueryNode
+	// Modified matches entities whose component was added or written after
+	// since, an explicit tick rather than the implicit one a Cursor tracks
+	// between Next passes. Use this when the caller keeps its own last-run
+	// tick across several short-lived Cursors instead of one long-lived one.
+	Modified(component Component, since uint64) QueryNode
+
+	// Near matches entities carrying component and orders a Cursor's
+	// iteration by proximity to point, nearest first, yielding at most k
+	// results. component must belong to a SpatialComponent whose index has
+	// been built with Rebuild.
+	Near(component Component, point []float64, k int) QueryNode
+	// WithinRadius matches entities carrying component whose indexed
+	// position is within r of point, ordering a Cursor's iteration nearest
+	// first. component must belong to a SpatialComponent whose index has
+	// been built with Rebuild.
+	WithinRadius(component Component, point []float64, r float64) QueryNode
+
+	// HasRelation matches entities with a kind relation to target.
+	HasRelation(kind RelationKind, target Entity) QueryNode
+	// HasAnyRelation matches entities with at least one kind relation.
+	HasAnyRelation(kind RelationKind) QueryNode
 }
 
 // QueryNode represents a node in the query tree that can be evaluated
@@ -47,6 +80,292 @@ type query struct {
 	root QueryNode
 }
 
+// tickOp identifies which tick column a tickNode filters against
+type tickOp int
+
+const (
+	tickAdded tickOp = iota
+	tickChanged
+	tickRemoved
+)
+
+// tickNode is a query term that filters on change-detection ticks rather
+// than static component membership. At the archetype level it behaves like
+// a leaf node (Added/Changed require the component, Removed excludes it);
+// the row-level filtering that makes it a true change-detection term
+// happens in Cursor, which collects tickNodes via collectTickNodes and
+// checks rowMatches for each candidate row.
+type tickNode struct {
+	component Component
+	op        tickOp
+}
+
+// Evaluate implements the QueryNode interface for tickNode at the
+// archetype level; row-level filtering happens separately in Cursor
+func (n *tickNode) Evaluate(archetype Archetype, storage Storage) bool {
+	var nodeMask mask.Mask
+	nodeMask.Mark(storage.RowIndexFor(n.component))
+	archeMask := archetype.Table().(mask.Maskable).Mask()
+	if n.op == tickRemoved {
+		return archeMask.ContainsNone(nodeMask)
+	}
+	return archeMask.ContainsAll(nodeMask)
+}
+
+// rowMatches reports whether row within arch passes this tick filter,
+// given the tick the cursor last observed changes up to
+func (n *tickNode) rowMatches(storage Storage, arch ArchetypeImpl, row int, sinceTick uint64) bool {
+	bit := storage.RowIndexFor(n.component)
+
+	if n.op == tickRemoved {
+		entry, err := arch.table.Entry(row)
+		if err != nil {
+			return false
+		}
+		return storage.removedSince(bit, entry.ID(), sinceTick)
+	}
+
+	ticks := arch.changeTicks
+	if n.op == tickAdded {
+		ticks = arch.addedTicks
+	}
+	col, ok := ticks[bit]
+	if !ok || row >= len(col) {
+		return false
+	}
+	return col[row] > sinceTick
+}
+
+// archetypeMayMatch reports, in O(1), whether arch could possibly satisfy
+// this filter for some row, using the archetype's running max tick instead
+// of scanning every row's tick column. Used by Cursor.Initialize to drop an
+// entire archetype from matchedStorages up front when it provably contains
+// no matching row, before Next ever falls back to rowMatches. Removed can't
+// be ruled out this way since removedSince tracks per-entry history rather
+// than a per-archetype max, so it always reports a possible match.
+func (n *tickNode) archetypeMayMatch(storage Storage, arch ArchetypeImpl, sinceTick uint64) bool {
+	if n.op == tickRemoved {
+		return true
+	}
+	bit := storage.RowIndexFor(n.component)
+	maxTicks := arch.maxChangeTick
+	if n.op == tickAdded {
+		maxTicks = arch.maxAddedTick
+	}
+	return maxTicks[bit] > sinceTick
+}
+
+// modifiedNode is a query term like tickNode's Changed op, but filtering
+// against an explicit since tick carried on the node itself instead of the
+// tick a Cursor tracks implicitly between Next passes.
+type modifiedNode struct {
+	component Component
+	since     uint64
+}
+
+// Evaluate implements the QueryNode interface for modifiedNode at the
+// archetype level; row-level filtering happens separately in Cursor
+func (n *modifiedNode) Evaluate(archetype Archetype, storage Storage) bool {
+	var nodeMask mask.Mask
+	nodeMask.Mark(storage.RowIndexFor(n.component))
+	archeMask := archetype.Table().(mask.Maskable).Mask()
+	return archeMask.ContainsAll(nodeMask)
+}
+
+// rowMatches reports whether row within arch was last changed after
+// n.since
+func (n *modifiedNode) rowMatches(storage Storage, arch ArchetypeImpl, row int) bool {
+	bit := storage.RowIndexFor(n.component)
+	col, ok := arch.changeTicks[bit]
+	if !ok || row >= len(col) {
+		return false
+	}
+	return col[row] > n.since
+}
+
+// archetypeMayMatch is the O(1) archetype-level counterpart to rowMatches,
+// used by Cursor.Initialize the same way tickNode.archetypeMayMatch is
+func (n *modifiedNode) archetypeMayMatch(storage Storage, arch ArchetypeImpl) bool {
+	bit := storage.RowIndexFor(n.component)
+	return arch.maxChangeTick[bit] > n.since
+}
+
+// collectModifiedNodes walks a query tree and gathers every modifiedNode
+// present, so Cursor can apply row-level filtering on top of the tree's
+// ordinary archetype-level Evaluate, the same way collectTickNodes does
+func collectModifiedNodes(node QueryNode) []*modifiedNode {
+	switch n := node.(type) {
+	case *modifiedNode:
+		return []*modifiedNode{n}
+	case *compositeNode:
+		var out []*modifiedNode
+		for _, child := range n.children {
+			out = append(out, collectModifiedNodes(child)...)
+		}
+		return out
+	case *query:
+		if n.root == nil {
+			return nil
+		}
+		return collectModifiedNodes(n.root)
+	default:
+		return nil
+	}
+}
+
+// optionalNode is a query term added by Maybe. Unlike every other QueryNode,
+// it never constrains archetype matching - Evaluate always reports true -
+// it only marks component as one Cursor should expose through a nullable
+// accessor (AccessibleComponent.OptionalFromCursor) for archetypes that
+// happen to carry it. Because Evaluate is unconditional, an optionalNode
+// should only ever be composed with And (or used alone): inside an Or or
+// Not it would trivially satisfy that operator for every archetype.
+type optionalNode struct {
+	component Component
+}
+
+// Evaluate implements the QueryNode interface for optionalNode; it always
+// matches, since Maybe exists to expose a nullable accessor rather than to
+// filter archetypes
+func (n *optionalNode) Evaluate(archetype Archetype, storage Storage) bool {
+	return true
+}
+
+// collectOptionalNodes walks a query tree and gathers every optionalNode
+// present, so Cursor can precompute a per-archetype presence plan for
+// AccessibleComponent.OptionalFromCursor, the same way collectTickNodes
+// gathers tickNodes
+func collectOptionalNodes(node QueryNode) []*optionalNode {
+	switch n := node.(type) {
+	case *optionalNode:
+		return []*optionalNode{n}
+	case *compositeNode:
+		var out []*optionalNode
+		for _, child := range n.children {
+			out = append(out, collectOptionalNodes(child)...)
+		}
+		return out
+	case *query:
+		if n.root == nil {
+			return nil
+		}
+		return collectOptionalNodes(n.root)
+	default:
+		return nil
+	}
+}
+
+// Maybe creates a query item exposing T's component as a nullable accessor
+// via AccessibleComponent.OptionalFromCursor, without requiring matched
+// archetypes to carry it - analogous to hecs's Option<&T> or Bevy's
+// Option<&T>. T must already have a component constructed for it via
+// FactoryNewComponent (or FactoryNewSpatialComponent) earlier in this
+// process, the same prerequisite LoadStorage documents for
+// componentTypeRegistry lookups, since there's otherwise no way to recover
+// a Component value from a bare type parameter. Maybe panics if none is
+// found.
+func Maybe[T any]() QueryNode {
+	var zero T
+	name := reflect.TypeOf(zero).String()
+	c, ok := lookupComponentType(name)
+	if !ok {
+		panic(bark.AddTrace(fmt.Errorf("warehouse: Maybe[%s]: no component constructed for this type; call FactoryNewComponent[%s]() first", name, name)))
+	}
+	return &optionalNode{component: c}
+}
+
+// spatialMode identifies whether a spatialNode is a k-nearest or a
+// radius search
+type spatialMode int
+
+const (
+	spatialNearest spatialMode = iota
+	spatialRadius
+)
+
+// spatialNode is a query term that orders a Cursor's iteration by proximity
+// to a point instead of (or in addition to) static component membership. At
+// the archetype level it behaves like a leaf node requiring component; the
+// nearest-first ordering itself is produced by Cursor consulting the
+// storage's HNSW index for component, built by SpatialComponent.Rebuild.
+type spatialNode struct {
+	component Component
+	point     []float64
+	k         int
+	radius    float64
+	mode      spatialMode
+}
+
+// Evaluate implements the QueryNode interface for spatialNode at the
+// archetype level; the actual proximity ordering happens in Cursor
+func (n *spatialNode) Evaluate(archetype Archetype, storage Storage) bool {
+	var nodeMask mask.Mask
+	nodeMask.Mark(storage.RowIndexFor(n.component))
+	archeMask := archetype.Table().(mask.Maskable).Mask()
+	return archeMask.ContainsAll(nodeMask)
+}
+
+// results queries the storage's HNSW index for this node's component,
+// returning candidates in nearest-first order
+func (n *spatialNode) results(storage Storage) []hnswCandidate {
+	idx, ok := storage.spatialIndexFor(storage.RowIndexFor(n.component))
+	if !ok {
+		return nil
+	}
+	if n.mode == spatialRadius {
+		return idx.RangeSearch(n.point, n.radius)
+	}
+	return idx.Search(n.point, n.k)
+}
+
+// collectSpatialNode walks a query tree and returns the first spatialNode
+// present, if any. Only one Near/WithinRadius term per query is supported:
+// it governs iteration order, and composing two proximity orderings in one
+// query isn't well-defined.
+func collectSpatialNode(node QueryNode) *spatialNode {
+	switch n := node.(type) {
+	case *spatialNode:
+		return n
+	case *compositeNode:
+		for _, child := range n.children {
+			if found := collectSpatialNode(child); found != nil {
+				return found
+			}
+		}
+		return nil
+	case *query:
+		if n.root == nil {
+			return nil
+		}
+		return collectSpatialNode(n.root)
+	default:
+		return nil
+	}
+}
+
+// collectTickNodes walks a query tree and gathers every tickNode present,
+// so Cursor can apply row-level change-detection filtering on top of the
+// tree's ordinary archetype-level Evaluate
+func collectTickNodes(node QueryNode) []*tickNode {
+	switch n := node.(type) {
+	case *tickNode:
+		return []*tickNode{n}
+	case *compositeNode:
+		var out []*tickNode
+		for _, child := range n.children {
+			out = append(out, collectTickNodes(child)...)
+		}
+		return out
+	case *query:
+		if n.root == nil {
+			return nil
+		}
+		return collectTickNodes(n.root)
+	default:
+		return nil
+	}
+}
+
 // newQuery creates a new empty query
 func newQuery() Query {
 	return &query{}
@@ -157,6 +476,87 @@ func (q *query) Not(items ...interface{}) QueryNode {
 	return node
 }
 
+// Added creates a query node matching entities whose component was added
+// since the cursor last observed this query
+func (q *query) Added(component Component) QueryNode {
+	node := &tickNode{component: component, op: tickAdded}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// Changed creates a query node matching entities whose component was added
+// or written since the cursor last observed this query
+func (q *query) Changed(component Component) QueryNode {
+	node := &tickNode{component: component, op: tickChanged}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// Removed creates a query node matching entities that had this component
+// removed since the cursor last observed this query
+func (q *query) Removed(component Component) QueryNode {
+	node := &tickNode{component: component, op: tickRemoved}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// Modified creates a query node matching entities whose component was added
+// or written after the explicit since tick
+func (q *query) Modified(component Component, since uint64) QueryNode {
+	node := &modifiedNode{component: component, since: since}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// Near creates a query node matching entities carrying component, ordering
+// a Cursor's iteration by proximity to point and yielding at most k results
+func (q *query) Near(component Component, point []float64, k int) QueryNode {
+	node := &spatialNode{component: component, point: point, k: k, mode: spatialNearest}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// WithinRadius creates a query node matching entities carrying component
+// whose indexed position is within r of point, ordering a Cursor's
+// iteration nearest first
+func (q *query) WithinRadius(component Component, point []float64, r float64) QueryNode {
+	node := &spatialNode{component: component, point: point, radius: r, mode: spatialRadius}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// HasRelation creates a query node matching entities with a kind relation
+// to target
+func (q *query) HasRelation(kind RelationKind, target Entity) QueryNode {
+	node := &relationNode{kind: kind, target: target}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
+// HasAnyRelation creates a query node matching entities with at least one
+// kind relation
+func (q *query) HasAnyRelation(kind RelationKind) QueryNode {
+	node := &relationNode{kind: kind}
+	if q.root == nil {
+		q.root = node
+	}
+	return node
+}
+
 // validateQueryItems checks if all items are of valid types for queries
 func (q *query) validateQueryItems(items ...interface{}) error {
 	for _, item := range items {
@@ -1,6 +1,7 @@
 package warehouse
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/TheBitDrifter/table"
@@ -304,6 +305,119 @@ func TestQueryComponentAccess(t *testing.T) {
 	}
 }
 
+// TestCursorChunks tests whole-archetype chunk iteration and the parallel
+// chunk fan-out
+func TestCursorChunks(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	if _, err := storage.NewEntities(5, posComp, velComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if _, err := storage.NewEntities(3, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	query := Factory.NewQuery()
+	queryNode := query.And(posComp)
+
+	// Chunks should cover the same total as Next()
+	cursor := Factory.NewCursor(queryNode, storage)
+	chunkTotal := 0
+	for _, view := range cursor.Chunks() {
+		chunkTotal += view.Len()
+		if len(view.EntityIDs()) != view.Len() {
+			t.Errorf("EntityIDs() length = %d, want %d", len(view.EntityIDs()), view.Len())
+		}
+	}
+	if chunkTotal != 8 {
+		t.Errorf("Chunks() total entities = %d, want 8", chunkTotal)
+	}
+
+	// ForEachChunkParallel should visit every matched archetype exactly once
+	cursor = Factory.NewCursor(queryNode, storage)
+	var mu sync.Mutex
+	parallelTotal := 0
+	cursor.ForEachChunkParallel(4, func(view ChunkView) {
+		mu.Lock()
+		parallelTotal += view.Len()
+		mu.Unlock()
+	})
+	if parallelTotal != 8 {
+		t.Errorf("ForEachChunkParallel() total entities = %d, want 8", parallelTotal)
+	}
+}
+
+// TestChangeDetectionQueries tests the Added/Changed/Removed query filters
+func TestChangeDetectionQueries(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(3, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	// Added: every entity just got Position, so it should show up once.
+	addedQuery := Factory.NewQuery()
+	addedNode := addedQuery.Added(posComp)
+	addedCursor := Factory.NewCursor(addedNode, storage)
+	count := 0
+	for addedCursor.Next() {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Added() matched %d entities, want 3", count)
+	}
+
+	// Re-running the same cursor after AdvanceTick should find nothing new.
+	storage.AdvanceTick()
+	count = 0
+	for addedCursor.Next() {
+		count++
+	}
+	if count != 0 {
+		t.Errorf("Added() matched %d entities after advancing tick, want 0", count)
+	}
+
+	// Changed: adding Velocity with a value should mark it changed.
+	if err := entities[0].AddComponentWithValue(velComp, Velocity{X: 1, Y: 1}); err != nil {
+		t.Fatalf("Failed to add velocity: %v", err)
+	}
+	changedQuery := Factory.NewQuery()
+	changedNode := changedQuery.Changed(velComp)
+	changedCursor := Factory.NewCursor(changedNode, storage)
+	count = 0
+	for changedCursor.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Changed() matched %d entities, want 1", count)
+	}
+
+	// Removed: removing Position from an entity should surface it, even
+	// though its current archetype no longer carries the component.
+	if err := entities[1].RemoveComponent(posComp); err != nil {
+		t.Fatalf("Failed to remove position: %v", err)
+	}
+	removedQuery := Factory.NewQuery()
+	removedNode := removedQuery.Removed(posComp)
+	removedCursor := Factory.NewCursor(removedNode, storage)
+	count = 0
+	for removedCursor.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Removed() matched %d entities, want 1", count)
+	}
+}
+
 // Helper function for float comparisons
 func almostEqual(a, b, epsilon float64) bool {
 	diff := a - b
@@ -0,0 +1,60 @@
+package warehouse
+
+// QueryState caches a query's matched archetypes and keeps the cache
+// current incrementally -- evaluating only each newly created archetype
+// against the query, rather than re-evaluating every archetype in storage
+// on every Cursor.Next -- the standard Bevy-style perf win for worlds with
+// many archetypes. Build one with Factory.NewQueryState and reuse it
+// across frames; build a Cursor from it with Factory.NewCursorFromState.
+type QueryState struct {
+	query      Query
+	storage    Storage
+	matched    []ArchetypeImpl
+	generation uint64
+}
+
+// NewQueryState creates a QueryState for q against storage: every
+// archetype storage already has is evaluated once up front, and a
+// subscription through storage.onArchetypeCreated keeps matched current as
+// storage creates new archetypes afterward.
+func (f factory) NewQueryState(q Query, storage Storage) *QueryState {
+	qs := &QueryState{query: q, storage: storage}
+	for _, arch := range storage.Archetypes() {
+		if q.Evaluate(arch, storage) {
+			qs.matched = append(qs.matched, arch)
+		}
+	}
+	qs.generation = storage.currentGeneration()
+	storage.onArchetypeCreated(func(arch ArchetypeImpl) {
+		if q.Evaluate(arch, storage) {
+			qs.matched = append(qs.matched, arch)
+		}
+		qs.generation = storage.currentGeneration()
+	})
+	return qs
+}
+
+// Matched returns the cached list of archetypes currently matching this
+// state's query. The returned slice is shared internal state: copy it
+// before mutating if the caller needs its own.
+func (qs *QueryState) Matched() []ArchetypeImpl {
+	return qs.matched
+}
+
+// Stale reports whether storage's generation counter has gone backwards
+// since this state last synced -- i.e. qs was built against a storage that
+// has since been replaced or reset out from under it, rather than simply
+// grown. There's no way to recover a stale QueryState; build a fresh one
+// against the current storage instead.
+func (qs *QueryState) Stale() bool {
+	return qs.storage.currentGeneration() < qs.generation
+}
+
+// NewCursorFromState creates a Cursor for qs.query against qs.storage,
+// reusing qs's cached matched-archetype slice directly instead of
+// re-evaluating every archetype the way a plain Factory.NewCursor does.
+func (f factory) NewCursorFromState(qs *QueryState) *Cursor {
+	c := newCursor(qs.query, qs.storage)
+	c.presetMatchedStorages = qs.matched
+	return c
+}
@@ -0,0 +1,82 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestQueryStateMatchesExistingArchetypes tests that NewQueryState
+// evaluates every archetype storage already has at construction time
+func TestQueryStateMatchesExistingArchetypes(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	if _, err := storage.NewEntities(3, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	if _, err := storage.NewEntities(2, posComp, velComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	query := Factory.NewQuery()
+	query.And(posComp)
+	qs := Factory.NewQueryState(query, storage)
+	if len(qs.Matched()) != 2 {
+		t.Fatalf("Matched() = %d archetypes, want 2", len(qs.Matched()))
+	}
+}
+
+// TestQueryStateUpdatesIncrementally tests that a QueryState picks up a
+// newly created matching archetype without being rebuilt
+func TestQueryStateUpdatesIncrementally(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	if _, err := storage.NewEntities(3, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	query := Factory.NewQuery()
+	query.And(posComp)
+	qs := Factory.NewQueryState(query, storage)
+	if len(qs.Matched()) != 1 {
+		t.Fatalf("Matched() before growth = %d archetypes, want 1", len(qs.Matched()))
+	}
+
+	if _, err := storage.NewEntities(2, posComp, velComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	if len(qs.Matched()) != 2 {
+		t.Fatalf("Matched() after growth = %d archetypes, want 2", len(qs.Matched()))
+	}
+}
+
+// TestCursorFromStateVisitsCachedArchetypes tests that a Cursor built from
+// a QueryState visits every entity in its cached matched archetypes
+func TestCursorFromStateVisitsCachedArchetypes(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	if _, err := storage.NewEntities(4, posComp); err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	query := Factory.NewQuery()
+	query.And(posComp)
+	qs := Factory.NewQueryState(query, storage)
+	cursor := Factory.NewCursorFromState(qs)
+	count := 0
+	for cursor.Next() {
+		count++
+	}
+	if count != 4 {
+		t.Errorf("Cursor from QueryState matched %d entities, want 4", count)
+	}
+}
@@ -0,0 +1,174 @@
+package warehouse
+
+import (
+	"reflect"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// RelationKind identifies a user-defined relation between entities, e.g.
+// "owns" or "targeting". A kind is identified by the Go type of its type
+// parameter: two FactoryNewRelation calls with the same T yield kinds that
+// share one identity and are not told apart.
+type RelationKind struct {
+	elementType table.ElementType
+}
+
+// FactoryNewRelation creates a new RelationKind. T need not carry any data
+// of its own -- it exists to give each kind a distinct, stable identity the
+// same way component types do.
+func FactoryNewRelation[T any]() RelationKind {
+	return RelationKind{elementType: table.FactoryNewElementType[T]()}
+}
+
+// id returns the kind's stable identity, used as a map key for an entity's
+// relation edges
+func (r RelationKind) id() reflect.Type {
+	return r.elementType.Type()
+}
+
+// parentMarker is an unexported marker type, giving the built-in
+// parent-child relation Entity.SetParent records edges under a stable,
+// distinct identity the same way a caller's own FactoryNewRelation[T]()
+// call would.
+type parentMarker struct{}
+
+// parentRelationKind is the RelationKind Entity.SetParent records
+// parent-child edges under, so hierarchy queries (HasRelation/
+// HasAnyRelation against parentRelationKind, Storage.Descendants) and
+// SetParent/Entity.Parent see the same edges.
+var parentRelationKind = FactoryNewRelation[parentMarker]()
+
+// relationEdge records that holder carries a kind relation pointing at a
+// target entity, kept on storage so a destroyed target's dangling edges
+// can be found without scanning every entity
+type relationEdge struct {
+	kind   RelationKind
+	holder Entity
+}
+
+// trackRelationHolder records that holder has a kind relation to target
+func (s *storage) trackRelationHolder(kind RelationKind, target, holder Entity) {
+	if s.relationHolders == nil {
+		s.relationHolders = make(map[table.EntryID][]relationEdge)
+	}
+	s.relationHolders[target.ID()] = append(s.relationHolders[target.ID()], relationEdge{kind: kind, holder: holder})
+}
+
+// untrackRelationHolder removes the record that holder has a kind relation
+// to target
+func (s *storage) untrackRelationHolder(kind RelationKind, target, holder Entity) {
+	edges := s.relationHolders[target.ID()]
+	for i, edge := range edges {
+		if edge.kind.id() == kind.id() && edge.holder.ID() == holder.ID() {
+			s.relationHolders[target.ID()] = append(edges[:i], edges[i+1:]...)
+			return
+		}
+	}
+}
+
+// removeDanglingRelations enqueues removal of every relation pointing at
+// target, across every holder, using the same locking/enqueue path as
+// Entity.EnqueueRemoveComponent. Called when target is destroyed.
+func (s *storage) removeDanglingRelations(target Entity) {
+	edges := s.relationHolders[target.ID()]
+	delete(s.relationHolders, target.ID())
+	for _, edge := range edges {
+		// Best effort: the holder may itself be mid-destruction in the same
+		// batch, in which case EnqueueRemoveRelation's validity checks make
+		// this a no-op.
+		_ = edge.holder.EnqueueRemoveRelation(edge.kind, target)
+	}
+}
+
+// Descendants walks the relation graph for kind starting at root, returning
+// every entity reachable by following holder edges back to root -- i.e.
+// every entity related to root, directly or transitively, via kind.
+func (s *storage) Descendants(root Entity, kind RelationKind) []Entity {
+	var out []Entity
+	visited := map[table.EntryID]bool{root.ID(): true}
+	queue := []Entity{root}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, edge := range s.relationHolders[cur.ID()] {
+			if edge.kind.id() != kind.id() || visited[edge.holder.ID()] {
+				continue
+			}
+			visited[edge.holder.ID()] = true
+			out = append(out, edge.holder)
+			queue = append(queue, edge.holder)
+		}
+	}
+	return out
+}
+
+// relationNode is a query term that filters by the presence of a
+// user-defined relation rather than archetype composition. Like tickNode,
+// it behaves as an always-true leaf at the archetype level; true per-row
+// filtering happens in Cursor via rowMatches, since relations aren't
+// encoded as archetype-defining component bits.
+type relationNode struct {
+	kind   RelationKind
+	target Entity // nil when matching HasAnyRelation
+}
+
+// Evaluate implements the QueryNode interface for relationNode; row-level
+// filtering happens separately in Cursor
+func (n *relationNode) Evaluate(archetype Archetype, storage Storage) bool {
+	return true
+}
+
+// rowMatches reports whether row within arch has a kind relation to target
+// (or any kind relation at all, if target is nil)
+func (n *relationNode) rowMatches(storage Storage, arch ArchetypeImpl, row int) bool {
+	entry, err := arch.table.Entry(row)
+	if err != nil {
+		return false
+	}
+	ent, err := storage.Entity(int(entry.ID()))
+	if err != nil {
+		return false
+	}
+	concrete, ok := ent.(*entity)
+	if !ok {
+		return false
+	}
+	targets, ok := concrete.relations[n.kind.id()]
+	if !ok {
+		return false
+	}
+	if n.target == nil {
+		return len(targets) > 0
+	}
+	for _, t := range targets {
+		if t.ID() == n.target.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRelationNodes walks a query tree and gathers every relationNode
+// present, so Cursor can apply row-level relation filtering on top of the
+// tree's ordinary archetype-level Evaluate
+func collectRelationNodes(node QueryNode) []*relationNode {
+	switch n := node.(type) {
+	case *relationNode:
+		return []*relationNode{n}
+	case *compositeNode:
+		var out []*relationNode
+		for _, child := range n.children {
+			out = append(out, collectRelationNodes(child)...)
+		}
+		return out
+	case *query:
+		if n.root == nil {
+			return nil
+		}
+		return collectRelationNodes(n.root)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestRelationAddRemoveAndQuery tests adding, querying, and removing
+// user-defined relations between entities
+func TestRelationAddRemoveAndQuery(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	owns := FactoryNewRelation[struct{}]()
+
+	entities, err := storage.NewEntities(3, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	owner, itemA, itemB := entities[0], entities[1], entities[2]
+
+	if err := owner.AddRelation(owns, itemA); err != nil {
+		t.Fatalf("AddRelation failed: %v", err)
+	}
+	if err := owner.AddRelation(owns, itemB); err != nil {
+		t.Fatalf("AddRelation failed: %v", err)
+	}
+
+	targets := owner.Targets(owns)
+	if len(targets) != 2 {
+		t.Fatalf("Targets() = %d entries, want 2", len(targets))
+	}
+
+	query := Factory.NewQuery()
+	node := query.HasRelation(owns, itemA)
+	cursor := Factory.NewCursor(node, storage)
+	count := 0
+	for cursor.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("HasRelation(owns, itemA) matched %d entities, want 1", count)
+	}
+
+	anyNode := query.HasAnyRelation(owns)
+	anyCursor := Factory.NewCursor(anyNode, storage)
+	anyCount := 0
+	for anyCursor.Next() {
+		anyCount++
+	}
+	if anyCount != 1 {
+		t.Errorf("HasAnyRelation(owns) matched %d entities, want 1", anyCount)
+	}
+
+	if err := owner.RemoveRelation(owns, itemA); err != nil {
+		t.Fatalf("RemoveRelation failed: %v", err)
+	}
+	if len(owner.Targets(owns)) != 1 {
+		t.Errorf("Targets() after removal = %d entries, want 1", len(owner.Targets(owns)))
+	}
+}
+
+// TestRelationDanglingRemovalOnDestroy tests that destroying a target
+// entity automatically removes dangling relations from its holders
+func TestRelationDanglingRemovalOnDestroy(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	targeting := FactoryNewRelation[struct{}]()
+
+	entities, err := storage.NewEntities(2, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	attacker, victim := entities[0], entities[1]
+
+	if err := attacker.AddRelation(targeting, victim); err != nil {
+		t.Fatalf("AddRelation failed: %v", err)
+	}
+
+	if err := storage.DestroyEntities(victim); err != nil {
+		t.Fatalf("DestroyEntities failed: %v", err)
+	}
+
+	if len(attacker.Targets(targeting)) != 0 {
+		t.Errorf("expected dangling relation to be removed, got %d targets", len(attacker.Targets(targeting)))
+	}
+}
+
+// TestDescendants tests walking a relation graph from a root entity
+func TestDescendants(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	childOf := FactoryNewRelation[struct{}]()
+
+	entities, err := storage.NewEntities(4, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	root, child, grandchild, unrelated := entities[0], entities[1], entities[2], entities[3]
+	_ = unrelated
+
+	if err := child.AddRelation(childOf, root); err != nil {
+		t.Fatalf("AddRelation failed: %v", err)
+	}
+	if err := grandchild.AddRelation(childOf, child); err != nil {
+		t.Fatalf("AddRelation failed: %v", err)
+	}
+
+	descendants := storage.Descendants(root, childOf)
+	if len(descendants) != 2 {
+		t.Fatalf("Descendants() = %d entries, want 2", len(descendants))
+	}
+}
+
+// TestSetParentRecordsRelation tests that SetParent records a
+// parentRelationKind edge so hierarchy queries see it the same way a
+// caller's own FactoryNewRelation edges are seen
+func TestSetParentRecordsRelation(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	entities, err := storage.NewEntities(2, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	parent, child := entities[0], entities[1]
+
+	if err := child.SetParent(parent, nil); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+
+	descendants := storage.Descendants(parent, parentRelationKind)
+	if len(descendants) != 1 || descendants[0].ID() != child.ID() {
+		t.Fatalf("Descendants(parent, parentRelationKind) = %v, want [child]", descendants)
+	}
+
+	node := Factory.NewQuery().HasRelation(parentRelationKind, parent)
+	cursor := Factory.NewCursor(node, storage)
+	count := 0
+	for cursor.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("HasRelation(parentRelationKind, parent) matched %d entities, want 1", count)
+	}
+}
+
+// TestDestroyEntitiesCascadesToChildren tests that destroying a parent
+// entity also destroys every descendant recorded via SetParent, and
+// invokes each destroyed entity's destroy callback
+func TestDestroyEntitiesCascadesToChildren(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	entities, err := storage.NewEntities(3, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	parent, child, grandchild := entities[0], entities[1], entities[2]
+
+	if err := child.SetParent(parent, nil); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	if err := grandchild.SetParent(child, nil); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+
+	var destroyed []table.EntryID
+	if err := child.SetDestroyCallback(func(en Entity) { destroyed = append(destroyed, en.ID()) }); err != nil {
+		t.Fatalf("SetDestroyCallback failed: %v", err)
+	}
+
+	if err := storage.DestroyEntities(parent); err != nil {
+		t.Fatalf("DestroyEntities failed: %v", err)
+	}
+
+	if len(destroyed) != 1 || destroyed[0] != child.ID() {
+		t.Errorf("destroy callback invocations = %v, want [%d]", destroyed, child.ID())
+	}
+
+	cursor := Factory.NewCursor(Factory.NewQuery().And(posComp), storage)
+	if count := cursor.TotalMatched(); count != 0 {
+		t.Errorf("entities remaining after cascading destroy = %d, want 0", count)
+	}
+}
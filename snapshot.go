@@ -0,0 +1,185 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// snapshotLockBit is the reserved storage lock bit held for the duration of
+// Snapshot, preventing structural changes while archetypes are walked and
+// written out.
+const snapshotLockBit uint32 = 30
+
+// snapshotVersion guards the wire format; LoadStorage rejects snapshots
+// written by an incompatible version.
+const snapshotVersion = 1
+
+// componentTypeRegistry maps a component's stable type name to the
+// Component value constructed for it, populated by FactoryNewComponent.
+// Snapshot restoration looks components up here by name: Go can't
+// synthesize a type from a string at runtime, so the program loading a
+// snapshot must already have constructed the same component variables
+// (e.g. via FactoryNewComponent[Position]()) that wrote it.
+var (
+	componentTypeRegistryMu sync.Mutex
+	componentTypeRegistry   = make(map[string]Component)
+)
+
+// registerComponentType records c under its Go type name for later
+// snapshot restoration
+func registerComponentType(c Component) {
+	componentTypeRegistryMu.Lock()
+	defer componentTypeRegistryMu.Unlock()
+	componentTypeRegistry[c.Type().String()] = c
+}
+
+// lookupComponentType finds a previously constructed Component by its Go
+// type name
+func lookupComponentType(name string) (Component, bool) {
+	componentTypeRegistryMu.Lock()
+	defer componentTypeRegistryMu.Unlock()
+	c, ok := componentTypeRegistry[name]
+	return c, ok
+}
+
+// snapshotArchetype is the on-disk representation of one archetype: its
+// component signature by type name, how many entities it held, and each
+// component's column encoded as a raw gob blob.
+type snapshotArchetype struct {
+	ComponentNames []string
+	EntityCount    int
+	Columns        [][]byte
+}
+
+// snapshotDoc is the top-level on-disk representation written by Snapshot
+// and read by Factory.LoadStorage.
+type snapshotDoc struct {
+	Version    int
+	Archetypes []snapshotArchetype
+}
+
+// Backend is a pluggable persistence target for snapshot bytes, letting
+// callers choose where a Storage's serialized form lives without changing
+// the encoding in Snapshot/LoadStorage. Only MemoryBackend ships here: a
+// disk- or database-backed implementation (e.g. BadgerDB) is a thin
+// adapter over WriteSnapshot/ReadSnapshot, but pulling in an external
+// key-value store is outside what this module vendors.
+type Backend interface {
+	WriteSnapshot(data []byte) error
+	ReadSnapshot() ([]byte, error)
+}
+
+// MemoryBackend is an in-process Backend, useful for tests and for passing
+// a snapshot between storages without touching disk.
+type MemoryBackend struct {
+	data []byte
+}
+
+// WriteSnapshot stores data, replacing any previous snapshot
+func (b *MemoryBackend) WriteSnapshot(data []byte) error {
+	b.data = append([]byte(nil), data...)
+	return nil
+}
+
+// ReadSnapshot returns the most recently written snapshot
+func (b *MemoryBackend) ReadSnapshot() ([]byte, error) {
+	if b.data == nil {
+		return nil, fmt.Errorf("snapshot: no data written to backend")
+	}
+	return b.data, nil
+}
+
+// Snapshot serializes every archetype's component columns to w: a header
+// recording the component type registry and row counts, followed by each
+// archetype's columns as raw blobs. Snapshotting respects Locked() the same
+// way the enqueue path does: any already-queued operations are drained
+// first, then the storage is locked for the duration of the write so
+// nothing structural changes while archetypes are walked.
+//
+// Entity IDs and recycled counts are not preserved across a snapshot:
+// restored entities are assigned fresh sequential IDs by the destination
+// storage's own entry index, since the table package doesn't expose a way
+// to recreate entries at a caller-chosen ID. Callers relying on recycled
+// counts surviving a restart (e.g. Entity.Parent's staleness check) should
+// treat every restored entity's relationships as needing to be
+// re-established.
+func (s *storage) Snapshot(w io.Writer) error {
+	if err := s.operationQueue.ProcessAll(s); err != nil {
+		return err
+	}
+	s.AddLock(snapshotLockBit)
+	defer s.RemoveLock(snapshotLockBit)
+
+	doc := snapshotDoc{Version: snapshotVersion}
+	for _, arch := range s.archetypes.asSlice {
+		tbl := arch.table
+		archDoc := snapshotArchetype{EntityCount: tbl.Length()}
+		for _, row := range tbl.Rows() {
+			elemType := reflect.Value(row).Type().Elem()
+			archDoc.ComponentNames = append(archDoc.ComponentNames, elemType.String())
+
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(reflect.Value(row).Interface()); err != nil {
+				return fmt.Errorf("snapshot: encode column %s: %w", elemType.String(), err)
+			}
+			archDoc.Columns = append(archDoc.Columns, buf.Bytes())
+		}
+		doc.Archetypes = append(doc.Archetypes, archDoc)
+	}
+
+	return gob.NewEncoder(w).Encode(doc)
+}
+
+// LoadStorage restores a Storage previously written by Snapshot. schema is
+// registered against the same component types the snapshot names; those
+// components must already exist in this process's componentTypeRegistry
+// (i.e. the caller has constructed them via FactoryNewComponent or
+// FactoryNewSpatialComponent) before calling LoadStorage.
+func (f factory) LoadStorage(schema table.Schema, r io.Reader) (Storage, error) {
+	var doc snapshotDoc
+	if err := gob.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("snapshot: decode: %w", err)
+	}
+	if doc.Version != snapshotVersion {
+		return nil, fmt.Errorf("snapshot: unsupported version %d (want %d)", doc.Version, snapshotVersion)
+	}
+
+	sto := newStorage(schema)
+	for _, archDoc := range doc.Archetypes {
+		components := make([]Component, 0, len(archDoc.ComponentNames))
+		for _, name := range archDoc.ComponentNames {
+			c, ok := lookupComponentType(name)
+			if !ok {
+				return nil, fmt.Errorf("snapshot: component %q was never constructed in this process; construct it (e.g. via FactoryNewComponent) before calling LoadStorage", name)
+			}
+			components = append(components, c)
+		}
+
+		arche, err := sto.NewOrExistingArchetype(components...)
+		if err != nil {
+			return nil, err
+		}
+		if err := arche.Generate(archDoc.EntityCount); err != nil {
+			return nil, err
+		}
+
+		for i, row := range arche.Table().Rows() {
+			elemType := reflect.Value(row).Type().Elem()
+			decoded := reflect.New(reflect.SliceOf(elemType))
+			if err := gob.NewDecoder(bytes.NewReader(archDoc.Columns[i])).Decode(decoded.Interface()); err != nil {
+				return nil, fmt.Errorf("snapshot: decode column %s: %w", elemType.String(), err)
+			}
+			values := decoded.Elem()
+			for j := 0; j < values.Len(); j++ {
+				reflect.Value(row).Index(j).Set(values.Index(j))
+			}
+		}
+	}
+	return sto, nil
+}
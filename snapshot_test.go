@@ -0,0 +1,115 @@
+package warehouse
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestSnapshotRoundTrip tests that component values survive a Snapshot and
+// LoadStorage cycle, across two archetypes
+func TestSnapshotRoundTrip(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	onlyPos, err := storage.NewEntities(2, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	*posComp.GetFromEntity(onlyPos[0]) = Position{X: 1, Y: 2}
+	*posComp.GetFromEntity(onlyPos[1]) = Position{X: 3, Y: 4}
+
+	posAndVel, err := storage.NewEntities(1, posComp, velComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	*posComp.GetFromEntity(posAndVel[0]) = Position{X: 5, Y: 6}
+	*velComp.GetFromEntity(posAndVel[0]) = Velocity{X: 7, Y: 8}
+
+	var buf bytes.Buffer
+	if err := storage.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	restoredSchema := table.Factory.NewSchema()
+	restored, err := Factory.LoadStorage(restoredSchema, &buf)
+	if err != nil {
+		t.Fatalf("LoadStorage failed: %v", err)
+	}
+
+	var gotOnlyPos, gotPosAndVel int
+	for _, arch := range restored.Archetypes() {
+		for i := 0; i < arch.table.Length(); i++ {
+			var hasVel bool
+			for _, row := range arch.table.Rows() {
+				if row.Type().Elem() == velComp.Component.Type() {
+					hasVel = true
+				}
+			}
+			if hasVel {
+				gotPosAndVel++
+			} else {
+				gotOnlyPos++
+			}
+		}
+	}
+	if gotOnlyPos != 2 {
+		t.Errorf("restored position-only entities = %d, want 2", gotOnlyPos)
+	}
+	if gotPosAndVel != 1 {
+		t.Errorf("restored position+velocity entities = %d, want 1", gotPosAndVel)
+	}
+
+	found := false
+	for _, arch := range restored.Archetypes() {
+		positions := posComp.Column(ChunkView{archetype: arch, table: arch.table})
+		for _, p := range positions {
+			if p == (Position{X: 5, Y: 6}) {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected to find restored Position{5,6} value")
+	}
+}
+
+// TestSnapshotMemoryBackend tests round-tripping a snapshot through a
+// MemoryBackend
+func TestSnapshotMemoryBackend(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	*posComp.GetFromEntity(entities[0]) = Position{X: 9, Y: 10}
+
+	var backend MemoryBackend
+	var buf bytes.Buffer
+	if err := storage.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if err := backend.WriteSnapshot(buf.Bytes()); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+
+	data, err := backend.ReadSnapshot()
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+
+	restoredSchema := table.Factory.NewSchema()
+	restored, err := Factory.LoadStorage(restoredSchema, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadStorage failed: %v", err)
+	}
+	if len(restored.Archetypes()) != 1 {
+		t.Fatalf("restored archetype count = %d, want 1", len(restored.Archetypes()))
+	}
+}
@@ -0,0 +1,370 @@
+package warehouse
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// Vector is implemented by component value types that can be indexed
+// spatially, exposing their position as a flat coordinate slice.
+type Vector interface {
+	Coordinates() []float64
+}
+
+const (
+	hnswM              = 16
+	hnswM0             = 2 * hnswM
+	hnswEfConstruction = 100
+)
+
+// hnswCandidate pairs an indexed id with its distance to a query point
+type hnswCandidate struct {
+	id   table.EntryID
+	dist float64
+}
+
+// hnswNode is a single indexed point, with its own neighbor list per layer
+type hnswNode struct {
+	id        table.EntryID
+	point     []float64
+	neighbors [][]table.EntryID // neighbors[layer]
+	tombstone bool
+}
+
+// hnswIndex is a multi-layer proximity graph supporting approximate
+// nearest-neighbor and range search over points tagged with entity ids.
+//
+// This follows the structure of the Hierarchical Navigable Small World
+// algorithm: a random top layer per inserted point drawn from a geometric
+// distribution, greedy descent from the entry point down to that layer,
+// and a bounded best-first search (ef) per layer below it to select up to
+// M neighbors (2M on layer 0) via the standard diversity heuristic: keep
+// candidate c only if no already-selected neighbor is closer to c than c
+// is to the query point. Deletions tombstone rather than unlink a node;
+// there is no compaction pass, so a heavily churned index accumulates dead
+// nodes until SpatialComponent.Rebuild rebuilds it from scratch.
+type hnswIndex struct {
+	mu       sync.RWMutex
+	nodes    map[table.EntryID]*hnswNode
+	entryID  table.EntryID
+	maxLevel int
+	levelize float64
+}
+
+func newHNSWIndex() *hnswIndex {
+	return &hnswIndex{
+		nodes:    make(map[table.EntryID]*hnswNode),
+		levelize: 1 / math.Log(float64(hnswM)),
+	}
+}
+
+func distance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// randomLevel draws a top layer for a new point from a geometric
+// distribution with parameter 1/ln(M)
+func (idx *hnswIndex) randomLevel() int {
+	return int(math.Floor(-math.Log(rand.Float64()) * idx.levelize))
+}
+
+// Insert adds or replaces the point indexed under id
+func (idx *hnswIndex) Insert(id table.EntryID, point []float64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	level := idx.randomLevel()
+	node := &hnswNode{id: id, point: point, neighbors: make([][]table.EntryID, level+1)}
+	idx.nodes[id] = node
+
+	if len(idx.nodes) == 1 {
+		idx.entryID = id
+		idx.maxLevel = level
+		return
+	}
+
+	entry := idx.nodes[idx.entryID]
+	cur, curDist := entry.id, distance(point, entry.point)
+
+	for l := idx.maxLevel; l > level; l-- {
+		cur, curDist = idx.greedyClosest(cur, curDist, point, l)
+	}
+
+	for l := minInt(idx.maxLevel, level); l >= 0; l-- {
+		candidates := idx.searchLayer(point, cur, hnswEfConstruction, l)
+		m := hnswM
+		if l == 0 {
+			m = hnswM0
+		}
+		selected := idx.selectNeighbors(candidates, m)
+		node.neighbors[l] = selected
+		for _, nid := range selected {
+			neighbor := idx.nodes[nid]
+			neighbor.neighbors[l] = append(neighbor.neighbors[l], id)
+			if len(neighbor.neighbors[l]) > m {
+				pruneCandidates := make([]hnswCandidate, len(neighbor.neighbors[l]))
+				for i, otherID := range neighbor.neighbors[l] {
+					pruneCandidates[i] = hnswCandidate{id: otherID, dist: distance(neighbor.point, idx.nodes[otherID].point)}
+				}
+				neighbor.neighbors[l] = idx.selectNeighbors(pruneCandidates, m)
+			}
+		}
+		if len(candidates) > 0 {
+			cur = candidates[0].id
+		}
+	}
+
+	if level > idx.maxLevel {
+		idx.maxLevel = level
+		idx.entryID = id
+	}
+}
+
+// greedyClosest walks from (curID, curDist) to the closest reachable
+// neighbor at layer, repeating until no neighbor improves on the current
+// best
+func (idx *hnswIndex) greedyClosest(curID table.EntryID, curDist float64, point []float64, layer int) (table.EntryID, float64) {
+	for improved := true; improved; {
+		improved = false
+		node := idx.nodes[curID]
+		if layer >= len(node.neighbors) {
+			break
+		}
+		for _, nid := range node.neighbors[layer] {
+			neighbor := idx.nodes[nid]
+			if neighbor.tombstone {
+				continue
+			}
+			d := distance(point, neighbor.point)
+			if d < curDist {
+				curID, curDist, improved = nid, d, true
+			}
+		}
+	}
+	return curID, curDist
+}
+
+// searchLayer runs a bounded best-first search from entry at layer,
+// returning up to ef candidates sorted nearest first
+func (idx *hnswIndex) searchLayer(point []float64, entry table.EntryID, ef int, layer int) []hnswCandidate {
+	visited := map[table.EntryID]bool{entry: true}
+	entryDist := distance(point, idx.nodes[entry].point)
+
+	frontier := []hnswCandidate{{id: entry, dist: entryDist}}
+	results := []hnswCandidate{{id: entry, dist: entryDist}}
+
+	for len(frontier) > 0 {
+		sort.Slice(frontier, func(i, j int) bool { return frontier[i].dist < frontier[j].dist })
+		c := frontier[0]
+		frontier = frontier[1:]
+
+		if len(results) >= ef {
+			sort.Slice(results, func(i, j int) bool { return results[i].dist < results[j].dist })
+			if c.dist > results[minInt(len(results), ef)-1].dist {
+				break
+			}
+		}
+
+		node := idx.nodes[c.id]
+		if layer >= len(node.neighbors) {
+			continue
+		}
+		for _, nid := range node.neighbors[layer] {
+			if visited[nid] {
+				continue
+			}
+			visited[nid] = true
+			neighbor := idx.nodes[nid]
+			if neighbor.tombstone {
+				continue
+			}
+			d := distance(point, neighbor.point)
+			frontier = append(frontier, hnswCandidate{id: nid, dist: d})
+			results = append(results, hnswCandidate{id: nid, dist: d})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].dist < results[j].dist })
+	if len(results) > ef {
+		results = results[:ef]
+	}
+	return results
+}
+
+// selectNeighbors applies the diversity heuristic to pick up to m
+// candidates: a candidate is kept only if no already-selected neighbor is
+// closer to it than it is to the query point
+func (idx *hnswIndex) selectNeighbors(candidates []hnswCandidate, m int) []table.EntryID {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+	selected := make([]hnswCandidate, 0, m)
+	for _, c := range candidates {
+		if len(selected) >= m {
+			break
+		}
+		keep := true
+		for _, s := range selected {
+			if distance(idx.nodes[c.id].point, idx.nodes[s.id].point) < c.dist {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			selected = append(selected, c)
+		}
+	}
+	ids := make([]table.EntryID, len(selected))
+	for i, s := range selected {
+		ids[i] = s.id
+	}
+	return ids
+}
+
+// entryPoint descends greedily from the top layer to layer 1, returning the
+// closest node found at layer 0's door
+func (idx *hnswIndex) entryPoint(point []float64) (table.EntryID, float64) {
+	entry := idx.nodes[idx.entryID]
+	cur, curDist := entry.id, distance(point, entry.point)
+	for l := idx.maxLevel; l > 0; l-- {
+		cur, curDist = idx.greedyClosest(cur, curDist, point, l)
+	}
+	return cur, curDist
+}
+
+// Search returns the k nearest indexed points to point, nearest first
+func (idx *hnswIndex) Search(point []float64, k int) []hnswCandidate {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if len(idx.nodes) == 0 || k <= 0 {
+		return nil
+	}
+	cur, _ := idx.entryPoint(point)
+	ef := k
+	if ef < hnswEfConstruction {
+		ef = hnswEfConstruction
+	}
+	candidates := idx.searchLayer(point, cur, ef, 0)
+	out := make([]hnswCandidate, 0, k)
+	for _, c := range candidates {
+		if idx.nodes[c.id].tombstone {
+			continue
+		}
+		out = append(out, c)
+		if len(out) == k {
+			break
+		}
+	}
+	return out
+}
+
+// RangeSearch returns every indexed point within radius r of point, nearest
+// first
+func (idx *hnswIndex) RangeSearch(point []float64, r float64) []hnswCandidate {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if len(idx.nodes) == 0 {
+		return nil
+	}
+	cur, _ := idx.entryPoint(point)
+	candidates := idx.searchLayer(point, cur, len(idx.nodes), 0)
+	out := make([]hnswCandidate, 0)
+	for _, c := range candidates {
+		if c.dist > r {
+			break
+		}
+		if idx.nodes[c.id].tombstone {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Remove tombstones id so future searches skip it
+func (idx *hnswIndex) Remove(id table.EntryID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if node, ok := idx.nodes[id]; ok {
+		node.tombstone = true
+	}
+}
+
+// spatialReindex inserts value's coordinates into the spatial index
+// registered for c on sto, if one has been built via SpatialComponent.Rebuild.
+// It's a no-op if c has no registered index, or if value doesn't implement
+// Vector (e.g. it's some other component sharing the same AddComponentWithValue/
+// SetComponent path). Called from the component-add/set paths that hand us an
+// actual value, so the index reflects it without a full Rebuild.
+func spatialReindex(sto Storage, c Component, id table.EntryID, value any) {
+	vec, ok := value.(Vector)
+	if !ok {
+		return
+	}
+	idx, ok := sto.spatialIndexFor(sto.RowIndexFor(c))
+	if !ok {
+		return
+	}
+	idx.Insert(id, vec.Coordinates())
+}
+
+// spatialRemove tombstones id out of the spatial index registered for c on
+// sto, if any. Called whenever c stops being attached to id, whether because
+// the component was removed or the entity itself was destroyed.
+func spatialRemove(sto Storage, c Component, id table.EntryID) {
+	idx, ok := sto.spatialIndexFor(sto.RowIndexFor(c))
+	if !ok {
+		return
+	}
+	idx.Remove(id)
+}
+
+// SpatialComponent wraps an AccessibleComponent whose value type exposes
+// coordinates, maintaining an HNSW nearest-neighbor index per storage so
+// query.Near and query.WithinRadius can filter by proximity in addition to
+// ordinary component composition.
+//
+// AddComponentWithValue, SetComponent, Changeset.Apply, RemoveComponent, and
+// DestroyEntities all keep a registered index up to date as they run, so
+// Rebuild only needs to be called once, before the first proximity query, and
+// again afterward if coordinates are ever written through a raw pointer (e.g.
+// *comp.GetFromEntity(e) = value) rather than one of those setters.
+type SpatialComponent[T Vector] struct {
+	AccessibleComponent[T]
+}
+
+// FactoryNewSpatialComponent creates a new SpatialComponent for type T
+func FactoryNewSpatialComponent[T Vector]() SpatialComponent[T] {
+	return SpatialComponent[T]{AccessibleComponent: FactoryNewComponent[T]()}
+}
+
+// Rebuild reindexes every entity in storage carrying this component
+func (sc SpatialComponent[T]) Rebuild(storage Storage) {
+	idx := newHNSWIndex()
+	q := Factory.NewQuery()
+	node := q.And(sc.Component)
+	cursor := Factory.NewCursor(node, storage)
+	for cursor.Next() {
+		en, err := cursor.CurrentEntity()
+		if err != nil {
+			continue
+		}
+		value := sc.GetFromCursor(cursor)
+		idx.Insert(en.ID(), (*value).Coordinates())
+	}
+	storage.setSpatialIndexFor(storage.RowIndexFor(sc.Component), idx)
+}
@@ -0,0 +1,94 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// Coordinates implements Vector for Position
+func (p Position) Coordinates() []float64 {
+	return []float64{p.X, p.Y}
+}
+
+// TestSpatialNearestNeighbor tests that query.Near returns entities ordered
+// by proximity to a point
+func TestSpatialNearestNeighbor(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewSpatialComponent[Position]()
+
+	points := []Position{
+		{X: 0, Y: 0},
+		{X: 10, Y: 0},
+		{X: 1, Y: 1},
+		{X: 50, Y: 50},
+		{X: 2, Y: 0},
+	}
+
+	entities, err := storage.NewEntities(len(points), posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	for i, e := range entities {
+		*posComp.GetFromEntity(e) = points[i]
+	}
+
+	posComp.Rebuild(storage)
+
+	query := Factory.NewQuery()
+	node := query.Near(posComp.Component, []float64{0, 0}, 3)
+	cursor := Factory.NewCursor(node, storage)
+
+	var distances []float64
+	for cursor.Next() {
+		distances = append(distances, cursor.CurrentDistance())
+	}
+
+	if len(distances) != 3 {
+		t.Fatalf("got %d results, want 3", len(distances))
+	}
+	for i := 1; i < len(distances); i++ {
+		if distances[i] < distances[i-1] {
+			t.Errorf("results not nearest-first: %v", distances)
+		}
+	}
+}
+
+// TestSpatialWithinRadius tests that query.WithinRadius filters by distance
+func TestSpatialWithinRadius(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewSpatialComponent[Position]()
+
+	points := []Position{
+		{X: 0, Y: 0},
+		{X: 1, Y: 0},
+		{X: 100, Y: 100},
+	}
+
+	entities, err := storage.NewEntities(len(points), posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	for i, e := range entities {
+		*posComp.GetFromEntity(e) = points[i]
+	}
+
+	posComp.Rebuild(storage)
+
+	query := Factory.NewQuery()
+	node := query.WithinRadius(posComp.Component, []float64{0, 0}, 5)
+	cursor := Factory.NewCursor(node, storage)
+
+	count := 0
+	for cursor.Next() {
+		count++
+		if cursor.CurrentDistance() > 5 {
+			t.Errorf("result distance %v exceeds radius 5", cursor.CurrentDistance())
+		}
+	}
+	if count != 2 {
+		t.Errorf("got %d results within radius, want 2", count)
+	}
+}
@@ -3,6 +3,7 @@ package warehouse
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/TheBitDrifter/mask"
 	"github.com/TheBitDrifter/table"
@@ -13,7 +14,11 @@ var _ Storage = &storage{}
 
 var (
 	globalEntryIndex = table.Factory.NewEntryIndex()
-	globalEntities   = make([]entity, 0)
+	// globalEntities holds the one live *entity per id, indexed by id-1, so
+	// Entity(id) and the *entity a caller already holds (from NewEntities,
+	// Generate, a Cursor, etc.) are always the same object -- never a stale
+	// copy -- and mutating one is visible through the other.
+	globalEntities = make([]*entity, 0)
 )
 
 // Storage defines the interface for entity storage and manipulation
@@ -29,11 +34,90 @@ type Storage interface {
 	AddLock(bit uint32)
 	RemoveLock(bit uint32)
 	Register(...Component)
-	tableFor(...Component) (table.Table, error)
+	tableFor(...Component) (table.Table, archetypeID, error)
+	archetypeFor(archetypeID) (ArchetypeImpl, bool)
 
 	TransferEntities(target Storage, entities ...Entity) error
 	Enqueue(EntityOperation)
 	Archetypes() []ArchetypeImpl
+
+	// AdvanceTick increments the storage's change-detection tick. Call it
+	// once per logical update (e.g. once per frame) so that
+	// query.Added/Changed/Removed filters know which writes are "new".
+	AdvanceTick()
+	// CurrentTick returns the storage's current change-detection tick.
+	CurrentTick() uint64
+	recordRemoval(bit uint32, id table.EntryID)
+	removedSince(bit uint32, id table.EntryID, sinceTick uint64) bool
+
+	// EnqueueSetComponent either overwrites a component value immediately
+	// or queues the overwrite if storage is locked.
+	EnqueueSetComponent(e Entity, c Component, value any) error
+	// EnqueueTransfer either transfers an entity immediately or queues the
+	// transfer if storage is locked.
+	EnqueueTransfer(target Storage, e Entity) error
+	// Commands returns a fluent CommandBuffer builder over this storage's
+	// deferred operation queue.
+	Commands() *CommandBuffer
+	// processQueue drains this storage's operation queue; used by World to
+	// process several storages' queues in a deterministic order.
+	processQueue() error
+
+	// spatialIndexFor returns the HNSW index registered for the component at
+	// bit, if SpatialComponent.Rebuild has built one.
+	spatialIndexFor(bit uint32) (*hnswIndex, bool)
+	// setSpatialIndexFor registers idx as the spatial index for the
+	// component at bit, replacing any previous index.
+	setSpatialIndexFor(bit uint32, idx *hnswIndex)
+
+	// Descendants walks the relation graph for kind starting at root,
+	// returning every entity related to root, directly or transitively.
+	Descendants(root Entity, kind RelationKind) []Entity
+	trackRelationHolder(kind RelationKind, target, holder Entity)
+	untrackRelationHolder(kind RelationKind, target, holder Entity)
+
+	// Snapshot serializes every archetype's component data to w, suitable
+	// for restoring later via Factory.LoadStorage.
+	Snapshot(w io.Writer) error
+
+	// AddObserver registers cb to be called with a TxReport after each
+	// batch of mutations containing an event matching filter.
+	AddObserver(name string, filter QueryNode, cb func(TxReport))
+	// RemoveObserver unregisters the observer previously added under name.
+	RemoveObserver(name string)
+	recordTxCreated(en Entity, to Archetype)
+	recordTxDestroyed(en Entity, from Archetype)
+	recordTxMoved(en Entity, from, to Archetype)
+	recordTxChanged(en Entity, c Component, before, after any, arch Archetype)
+	beginTxBatch()
+	endTxBatch()
+
+	// RunParallel matches query and fans its chunks out across a worker
+	// pool, per opts. See ParallelOptions and the method doc on *storage
+	// for the locking and data-race caveats.
+	RunParallel(query QueryNode, fn func(ChunkView), opts ParallelOptions)
+
+	// fireArchetypeTriggers runs from/to's OnPut/OnRemove/OnReplace
+	// triggers for an entity that just moved archetypes, deferring them
+	// through the operation queue if storage is locked.
+	fireArchetypeTriggers(en Entity, from, to Archetype)
+
+	// onArchetypeCreated registers fn to run whenever storage creates a new
+	// archetype (not when an existing one is reused). QueryState subscribes
+	// through this to update its cached matches incrementally instead of
+	// re-evaluating every archetype on each Cursor.Next.
+	onArchetypeCreated(fn func(ArchetypeImpl))
+	// currentGeneration returns how many archetypes storage has ever
+	// created. QueryState caches this alongside its matched archetype list.
+	currentGeneration() uint64
+
+	// archetypeViaAddEdge resolves the archetype AddComponent(c) should move
+	// an entity into, consulting from's cached add edge for c first. See the
+	// method on *storage for the cache-miss/caching behavior.
+	archetypeViaAddEdge(from ArchetypeImpl, hasFrom bool, c Component, allComponents []Component) (Archetype, error)
+	// archetypeViaRemoveEdge is archetypeViaAddEdge's counterpart for
+	// RemoveComponent.
+	archetypeViaRemoveEdge(from ArchetypeImpl, hasFrom bool, c Component, remainingComponents []Component) (Archetype, error)
 }
 
 // storage implements the Storage interface
@@ -42,6 +126,35 @@ type storage struct {
 	schema         table.Schema
 	archetypes     *archetypes
 	operationQueue EntityOperationsQueue
+	tick           uint64
+	// removals tracks the last tick a component was removed from a given
+	// entity, keyed by component bit, so query.Removed can find entities
+	// that recently lost a component even though their current archetype
+	// no longer carries it.
+	removals map[uint32]map[table.EntryID]uint64
+	// spatialIndices holds the HNSW index built for each SpatialComponent
+	// registered against this storage, keyed by component bit.
+	spatialIndices map[uint32]*hnswIndex
+	// relationHolders tracks which entities hold a relation to a given
+	// target, keyed by the target's entity id, so a destroyed target's
+	// dangling relations can be found without scanning every entity.
+	relationHolders map[table.EntryID][]relationEdge
+	// observers are the registered transaction observers, checked against
+	// pendingTxEvents once a mutation batch ends.
+	observers []txObserver
+	// pendingTxEvents accumulates mutation events for the batch currently
+	// in progress; see beginTxBatch/endTxBatch.
+	pendingTxEvents []txEvent
+	// txDepth counts nested beginTxBatch calls, so a queue drain applying
+	// several operations dispatches one TxReport instead of one per op.
+	txDepth int
+
+	// archetypeObservers are callbacks notified whenever a new archetype is
+	// created; see onArchetypeCreated.
+	archetypeObservers []func(ArchetypeImpl)
+	// generation counts how many archetypes this storage has ever created,
+	// bumped by notifyArchetypeCreated alongside each observer callback.
+	generation uint64
 }
 
 // archetypes manages archetype collections and identification
@@ -61,13 +174,75 @@ func newStorage(schema table.Schema) Storage {
 		archetypes:     archetypes,
 		schema:         schema,
 		operationQueue: &entityOperationsQueue{},
+		removals:       make(map[uint32]map[table.EntryID]uint64),
+		spatialIndices: make(map[uint32]*hnswIndex),
 	}
 	return storage
 }
 
+// AdvanceTick increments the change-detection tick
+func (sto *storage) AdvanceTick() {
+	sto.tick++
+}
+
+// CurrentTick returns the current change-detection tick
+func (sto *storage) CurrentTick() uint64 {
+	return sto.tick
+}
+
+// recordRemoval stamps the current tick as the last time the component at
+// bit was removed from the given entity
+func (sto *storage) recordRemoval(bit uint32, id table.EntryID) {
+	byEntity, ok := sto.removals[bit]
+	if !ok {
+		byEntity = make(map[table.EntryID]uint64)
+		sto.removals[bit] = byEntity
+	}
+	byEntity[id] = sto.tick
+}
+
+// removedSince reports whether the component at bit was removed from the
+// given entity after sinceTick
+func (sto *storage) removedSince(bit uint32, id table.EntryID, sinceTick uint64) bool {
+	byEntity, ok := sto.removals[bit]
+	if !ok {
+		return false
+	}
+	tick, ok := byEntity[id]
+	if !ok {
+		return false
+	}
+	return tick > sinceTick
+}
+
 // Entity retrieves an entity by ID
 func (sto *storage) Entity(id int) (Entity, error) {
-	return &globalEntities[id-1], nil
+	index := id - 1
+	if index < 0 || index >= len(globalEntities) || globalEntities[index] == nil {
+		return nil, fmt.Errorf("no entity with id %d", id)
+	}
+	return globalEntities[index], nil
+}
+
+// rebindEntityID moves en's live entry to newID, growing globalEntities if
+// needed and clearing its old slot. A TransferEntries call normally
+// preserves en's original id by recycling it straight into the destination
+// table, but the vendored entry index refuses to recycle an id when doing
+// so would free every id it has ever allocated (see
+// table.entryIndex.RecycleEntries) -- the first transfer ever run against a
+// storage, for example. When that happens the moved row lands under a
+// freshly minted id instead, so this follows en there.
+func rebindEntityID(en *entity, newID table.EntryID) {
+	if oldIndex := int(en.id) - 1; oldIndex >= 0 && oldIndex < len(globalEntities) {
+		globalEntities[oldIndex] = nil
+	}
+	en.id = newID
+	if neededLen := int(newID); neededLen > len(globalEntities) {
+		grown := make([]*entity, neededLen)
+		copy(grown, globalEntities)
+		globalEntities = grown
+	}
+	globalEntities[newID-1] = en
 }
 
 // NewOrExistingArchetype gets an existing archetype matching the component signature or creates a new one
@@ -90,14 +265,72 @@ func (sto *storage) NewOrExistingArchetype(components ...Component) (Archetype,
 	sto.archetypes.asSlice = append(sto.archetypes.asSlice, created)
 	sto.archetypes.idsGroupedByMask[entityMask] = created.id
 	sto.archetypes.nextID++
+	sto.notifyArchetypeCreated(created)
 	return &created, nil
 }
 
+// archetypeViaAddEdge resolves the archetype AddComponent(c) should move an
+// entity into, consulting from's cached add edge for c first and falling
+// back to NewOrExistingArchetype -- caching the result as a new edge on
+// from -- on a miss. allComponents is the entity's full post-add component
+// set, passed through to NewOrExistingArchetype on a miss. hasFrom is false
+// the rare time the entity's current archetype can't be resolved, in which
+// case no edge is consulted or cached.
+func (sto *storage) archetypeViaAddEdge(
+	from ArchetypeImpl, hasFrom bool, c Component, allComponents []Component,
+) (Archetype, error) {
+	if hasFrom {
+		if destID, ok := from.addEdgeFor(c); ok {
+			if dest, ok := sto.archetypeFor(destID); ok {
+				return dest, nil
+			}
+		}
+	}
+	dest, err := sto.NewOrExistingArchetype(allComponents...)
+	if err != nil {
+		return nil, err
+	}
+	if hasFrom {
+		if destImpl, ok := asArchetypeImpl(dest); ok {
+			from.setAddEdge(c, destImpl.id)
+		}
+	}
+	return dest, nil
+}
+
+// archetypeViaRemoveEdge is archetypeViaAddEdge's counterpart for
+// RemoveComponent; remainingComponents is the entity's component set with c
+// already excluded.
+func (sto *storage) archetypeViaRemoveEdge(
+	from ArchetypeImpl, hasFrom bool, c Component, remainingComponents []Component,
+) (Archetype, error) {
+	if hasFrom {
+		if destID, ok := from.removeEdgeFor(c); ok {
+			if dest, ok := sto.archetypeFor(destID); ok {
+				return dest, nil
+			}
+		}
+	}
+	dest, err := sto.NewOrExistingArchetype(remainingComponents...)
+	if err != nil {
+		return nil, err
+	}
+	if hasFrom {
+		if destImpl, ok := asArchetypeImpl(dest); ok {
+			from.setRemoveEdge(c, destImpl.id)
+		}
+	}
+	return dest, nil
+}
+
 // NewEntities creates n new entities with the specified components
 func (sto *storage) NewEntities(n int, components ...Component) ([]Entity, error) {
 	if sto.Locked() {
 		return nil, errors.New("storage is locked")
 	}
+	sto.beginTxBatch()
+	defer sto.endTxBatch()
+
 	var entityMask mask.Mask
 	for _, component := range components {
 		sto.schema.Register(component)
@@ -123,22 +356,33 @@ func (sto *storage) NewEntities(n int, components ...Component) ([]Entity, error
 	neededCap := currentLen + n
 	if cap(globalEntities) < neededCap {
 		newCap := max(neededCap, 2*cap(globalEntities))
-		newEntities := make([]entity, currentLen, newCap)
+		newEntities := make([]*entity, currentLen, newCap)
 		copy(newEntities, globalEntities)
 		globalEntities = newEntities
 	}
 	globalEntities = globalEntities[:neededCap]
 
+	archID := archetypeID(entityArchetype.ID())
+	archImpl, hasArchImpl := asArchetypeImpl(entityArchetype)
+	tick := sto.tick
 	entities := make([]Entity, n)
 	for i, entry := range entries {
 		en := &entity{
-			Entry:      entry,
-			sto:        sto,
-			id:         entry.ID(),
-			components: components,
+			Entry:       entry,
+			sto:         sto,
+			id:          entry.ID(),
+			components:  components,
+			archetypeID: archID,
+			row:         uint32(entry.Index()),
 		}
 		entities[i] = en
-		globalEntities[currentLen+i] = *en
+		globalEntities[currentLen+i] = en
+		if hasArchImpl {
+			for _, c := range components {
+				archImpl.stampAdded(sto.RowIndexFor(c), entry.Index(), tick)
+			}
+		}
+		sto.recordTxCreated(en, entityArchetype)
 	}
 
 	return entities, nil
@@ -190,17 +434,41 @@ func (s *storage) EnqueueNewEntities(count int, components ...Component) error {
 	return nil
 }
 
-// DestroyEntities removes entities from storage
+// DestroyEntities removes entities from storage. Destroying an entity
+// cascades to destroy every descendant it has via Entity.SetParent (found
+// through parentRelationKind), and invokes each destroyed entity's destroy
+// callback, if one was set via SetDestroyCallback/SetParent.
 func (s *storage) DestroyEntities(entities ...Entity) error {
 	if s.Locked() {
 		return errors.New("storage is locked")
 	}
+	s.beginTxBatch()
+	defer s.endTxBatch()
+
+	seen := make(map[table.EntryID]bool, len(entities))
+	var all []Entity
+	var collect func(en Entity)
+	collect = func(en Entity) {
+		if en == nil || seen[en.ID()] {
+			return
+		}
+		seen[en.ID()] = true
+		all = append(all, en)
+		for _, child := range s.Descendants(en, parentRelationKind) {
+			collect(child)
+		}
+	}
+	for _, en := range entities {
+		collect(en)
+	}
+	entities = all
+
 	tableGroups := make(map[table.Table][]int)
 	for _, entity := range entities {
 		if entity == nil {
 			continue
 		}
-		tableGroups[entity.Table()] = append(tableGroups[entity.Table()], int(entity.ID()))
+		tableGroups[entity.Table()] = append(tableGroups[entity.Table()], entity.Index())
 	}
 	for tbl, ids := range tableGroups {
 		_, err := tbl.DeleteEntries(ids...)
@@ -212,9 +480,21 @@ func (s *storage) DestroyEntities(entities ...Entity) error {
 		if en == nil {
 			continue
 		}
+		if concrete, ok := en.(*entity); ok {
+			if arch, ok := s.archetypeFor(concrete.archetypeID); ok {
+				s.recordTxDestroyed(en, arch)
+			}
+			if concrete.relationships.onDestroy != nil {
+				concrete.relationships.onDestroy(en)
+			}
+			for _, c := range concrete.components {
+				spatialRemove(s, c, concrete.id)
+			}
+		}
+		s.removeDanglingRelations(en)
 		index := en.ID() - 1
 		if int(index) < len(globalEntities) {
-			globalEntities[index] = entity{}
+			globalEntities[index] = nil
 		}
 	}
 	return nil
@@ -235,6 +515,47 @@ func (s *storage) EnqueueDestroyEntities(entities ...Entity) error {
 	return nil
 }
 
+// EnqueueSetComponent either overwrites the component value directly or
+// queues the overwrite if storage is locked
+func (s *storage) EnqueueSetComponent(e Entity, c Component, value any) error {
+	if !s.Locked() {
+		return e.SetComponent(c, value)
+	}
+	s.operationQueue.Enqueue(SetComponentOperation{
+		entity:    e,
+		recycled:  e.Recycled(),
+		component: c,
+		value:     value,
+		storage:   s,
+	})
+	return nil
+}
+
+// EnqueueTransfer either transfers the entity directly or queues the
+// transfer if storage is locked
+func (s *storage) EnqueueTransfer(target Storage, e Entity) error {
+	if !s.Locked() {
+		return s.TransferEntities(target, e)
+	}
+	s.operationQueue.Enqueue(TransferEntityOperation{
+		target:   target,
+		entity:   e,
+		recycled: e.Recycled(),
+	})
+	return nil
+}
+
+// Commands returns a fluent CommandBuffer builder over this storage's
+// deferred operation queue
+func (s *storage) Commands() *CommandBuffer {
+	return &CommandBuffer{storage: s}
+}
+
+// processQueue drains this storage's operation queue
+func (s *storage) processQueue() error {
+	return s.operationQueue.ProcessAll(s)
+}
+
 // TransferEntities moves entities from this storage to the target storage
 func (s *storage) TransferEntities(target Storage, entities ...Entity) error {
 	if s.Locked() {
@@ -243,7 +564,7 @@ func (s *storage) TransferEntities(target Storage, entities ...Entity) error {
 	for _, en := range entities {
 		comps := en.Components()
 		target.Register(comps...)
-		targetTbl, err := target.tableFor(comps...)
+		targetTbl, destID, err := target.tableFor(comps...)
 		if err != nil {
 			return err
 		}
@@ -253,6 +574,10 @@ func (s *storage) TransferEntities(target Storage, entities ...Entity) error {
 			return err
 		}
 		en.SetStorage(target)
+		if concrete, ok := en.(*entity); ok {
+			concrete.archetypeID = destID
+			concrete.row = uint32(concrete.Index())
+		}
 	}
 	return nil
 }
@@ -276,8 +601,9 @@ func (s *storage) Archetypes() []ArchetypeImpl {
 	return s.archetypes.asSlice
 }
 
-// tableFor gets or creates a table for the given component set
-func (s *storage) tableFor(comps ...Component) (table.Table, error) {
+// tableFor gets or creates a table for the given component set, also
+// returning the id of the archetype that owns it
+func (s *storage) tableFor(comps ...Component) (table.Table, archetypeID, error) {
 	archeMask := mask.Mask{}
 	for _, c := range comps {
 		bit := s.RowIndexFor(c)
@@ -290,12 +616,54 @@ func (s *storage) tableFor(comps ...Component) (table.Table, error) {
 		decrement++
 		created, err := newArchetype(s, globalEntryIndex, s.archetypes.nextID, comps...)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		s.archetypes.asSlice = append(s.archetypes.asSlice, created)
 		s.archetypes.nextID++
 		id = s.archetypes.nextID
+		s.notifyArchetypeCreated(created)
 	}
 	arche := s.archetypes.asSlice[id-archetypeID(decrement)]
-	return arche.table, nil
+	return arche.table, arche.id, nil
+}
+
+// onArchetypeCreated registers fn to run whenever storage creates a new
+// archetype. See the Storage interface doc for why QueryState uses this.
+func (s *storage) onArchetypeCreated(fn func(ArchetypeImpl)) {
+	s.archetypeObservers = append(s.archetypeObservers, fn)
+}
+
+// notifyArchetypeCreated bumps the generation counter and runs every
+// registered archetype-creation observer for arch
+func (s *storage) notifyArchetypeCreated(arch ArchetypeImpl) {
+	s.generation++
+	for _, fn := range s.archetypeObservers {
+		fn(arch)
+	}
+}
+
+// currentGeneration returns how many archetypes storage has ever created
+func (s *storage) currentGeneration() uint64 {
+	return s.generation
+}
+
+// archetypeFor resolves a cached archetype id to its archetype, giving
+// entities an O(1) path from a stored location back to their table without
+// re-walking the archetype/mask lookup
+func (s *storage) archetypeFor(id archetypeID) (ArchetypeImpl, bool) {
+	if id == 0 || int(id) > len(s.archetypes.asSlice) {
+		return ArchetypeImpl{}, false
+	}
+	return s.archetypes.asSlice[id-1], true
+}
+
+// spatialIndexFor returns the HNSW index registered for the component at bit
+func (s *storage) spatialIndexFor(bit uint32) (*hnswIndex, bool) {
+	idx, ok := s.spatialIndices[bit]
+	return idx, ok
+}
+
+// setSpatialIndexFor registers idx as the spatial index for the component at bit
+func (s *storage) setSpatialIndexFor(bit uint32, idx *hnswIndex) {
+	s.spatialIndices[bit] = idx
 }
@@ -196,6 +196,67 @@ func TestStorageLocking(t *testing.T) {
 	}
 }
 
+// TestCommandBufferAndWorld tests the fluent CommandBuffer builder and
+// World's deterministic cross-storage queue draining
+func TestCommandBufferAndWorld(t *testing.T) {
+	schema1 := table.Factory.NewSchema()
+	storage1 := Factory.NewStorage(schema1)
+
+	schema2 := table.Factory.NewSchema()
+	storage2 := Factory.NewStorage(schema2)
+
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage1.NewEntities(3, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+
+	// Lock storage1 so every command below is queued rather than applied
+	// immediately.
+	storage1.AddLock(1)
+
+	storage1.Commands().
+		AddComponentWithValue(entities[0], velComp, Velocity{X: 1, Y: 1}).
+		SetComponent(entities[0], posComp, Position{X: 9, Y: 9}).
+		BatchAddComponent(entities, velComp).
+		Transfer(storage2, entities[1])
+
+	// Nothing should have applied yet: storage1 is still locked.
+	if entities[0].Table().Contains(velComp) {
+		t.Errorf("component added while storage was locked")
+	}
+
+	world := Factory.NewWorld()
+	world.Add(storage1).Add(storage2)
+
+	// Still locked: World should skip it without error.
+	if err := world.ProcessAll(); err != nil {
+		t.Fatalf("ProcessAll() on locked storage returned error: %v", err)
+	}
+	if entities[0].Table().Contains(velComp) {
+		t.Errorf("component added while storage was locked")
+	}
+
+	storage1.RemoveLock(1)
+
+	if err := world.ProcessAll(); err != nil {
+		t.Fatalf("ProcessAll() failed: %v", err)
+	}
+
+	if !entities[0].Table().Contains(velComp) {
+		t.Errorf("BatchAddComponent/AddComponentWithValue did not apply")
+	}
+	posPtr := posComp.GetFromEntity(entities[0])
+	if posPtr.X != 9 || posPtr.Y != 9 {
+		t.Errorf("SetComponent did not apply, got %+v", *posPtr)
+	}
+	if entities[1].Storage() != storage2 {
+		t.Errorf("Transfer did not apply, entity still on %v", entities[1].Storage())
+	}
+}
+
 // TestEntityTransfer tests transferring entities between storages
 func TestEntityTransfer(t *testing.T) {
 	// Create two storages
@@ -0,0 +1,127 @@
+package warehouse
+
+import "sync"
+
+// archetypeTriggers holds the put/remove/replace callbacks registered for a
+// single archetype. It's held behind a pointer shared across every
+// ArchetypeImpl value for that archetype's stable component-set ID, the
+// same way ArchetypeImpl.changeTicks is shared, so a trigger registered
+// through one copy of an archetype fires no matter which copy produced the
+// move that triggers it.
+type archetypeTriggers struct {
+	mu        sync.Mutex
+	onPut     []func(Entity)
+	onRemove  []func(Entity)
+	onReplace []func(Entity, Archetype, Archetype)
+}
+
+func (t *archetypeTriggers) addPut(fn func(Entity)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onPut = append(t.onPut, fn)
+}
+
+func (t *archetypeTriggers) addRemove(fn func(Entity)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onRemove = append(t.onRemove, fn)
+}
+
+func (t *archetypeTriggers) addReplace(fn func(Entity, Archetype, Archetype)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onReplace = append(t.onReplace, fn)
+}
+
+func (t *archetypeTriggers) firePut(en Entity) {
+	t.mu.Lock()
+	cbs := append([]func(Entity){}, t.onPut...)
+	t.mu.Unlock()
+	for _, cb := range cbs {
+		cb(en)
+	}
+}
+
+func (t *archetypeTriggers) fireRemove(en Entity) {
+	t.mu.Lock()
+	cbs := append([]func(Entity){}, t.onRemove...)
+	t.mu.Unlock()
+	for _, cb := range cbs {
+		cb(en)
+	}
+}
+
+func (t *archetypeTriggers) fireReplace(en Entity, from, to Archetype) {
+	t.mu.Lock()
+	cbs := append([]func(Entity, Archetype, Archetype){}, t.onReplace...)
+	t.mu.Unlock()
+	for _, cb := range cbs {
+		cb(en, from, to)
+	}
+}
+
+// triggersOf returns a's trigger set. NewOrExistingArchetype returns a
+// pointer for a newly created archetype but a value for one that already
+// existed, so this has to recognize both forms.
+func triggersOf(a Archetype) *archetypeTriggers {
+	switch v := a.(type) {
+	case *ArchetypeImpl:
+		return v.triggers
+	case ArchetypeImpl:
+		return v.triggers
+	default:
+		return nil
+	}
+}
+
+// runArchetypeTriggers fires from's OnRemove, to's OnPut, and both
+// archetypes' OnReplace callbacks for en, which just moved from from to to
+// via TransferEntries. Either may be nil, e.g. from is nil when the entity's
+// previous archetype couldn't be resolved from its cached location, in
+// which case the corresponding callbacks are skipped.
+func runArchetypeTriggers(en Entity, from, to Archetype) {
+	fromTriggers := triggersOf(from)
+	toTriggers := triggersOf(to)
+
+	if fromTriggers != nil {
+		fromTriggers.fireRemove(en)
+	}
+	if toTriggers != nil {
+		toTriggers.firePut(en)
+	}
+	if fromTriggers != nil {
+		fromTriggers.fireReplace(en, from, to)
+	}
+	if toTriggers != nil && toTriggers != fromTriggers {
+		toTriggers.fireReplace(en, from, to)
+	}
+}
+
+// ArchetypeTriggerOperation fires the put/remove/replace triggers recorded
+// by fireArchetypeTriggers for an entity move that happened while storage
+// was locked, so trigger bodies can freely call AddComponent/RemoveComponent
+// on other entities once the queue drains instead of deadlocking or
+// invalidating an in-flight iteration.
+type ArchetypeTriggerOperation struct {
+	entity   Entity
+	from, to Archetype
+}
+
+// Apply fires the queued triggers if the entity is still valid
+func (op ArchetypeTriggerOperation) Apply(sto Storage) error {
+	if !op.entity.Valid() {
+		return nil
+	}
+	runArchetypeTriggers(op.entity, op.from, op.to)
+	return nil
+}
+
+// fireArchetypeTriggers runs from/to's triggers for en's move immediately,
+// or queues them to run once storage unlocks if it's currently locked
+func (s *storage) fireArchetypeTriggers(en Entity, from, to Archetype) {
+	if s.Locked() {
+		s.Enqueue(ArchetypeTriggerOperation{entity: en, from: from, to: to})
+		return
+	}
+	runArchetypeTriggers(en, from, to)
+}
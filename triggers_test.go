@@ -0,0 +1,118 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/TheBitDrifter/table"
+)
+
+// TestArchetypeTriggersFireOnPutAndRemove tests that OnPut/OnRemove fire
+// for the archetypes an entity moves into and out of
+func TestArchetypeTriggersFireOnPutAndRemove(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	entity := entities[0]
+
+	posOnly, err := storage.NewOrExistingArchetype(posComp)
+	if err != nil {
+		t.Fatalf("Failed to resolve archetype: %v", err)
+	}
+	var putFired, removeFired int
+	posOnly.OnRemove(func(e Entity) { removeFired++ })
+
+	if err := entity.AddComponent(velComp); err != nil {
+		t.Fatalf("AddComponent failed: %v", err)
+	}
+	if removeFired != 1 {
+		t.Errorf("expected OnRemove to fire once leaving the position-only archetype, fired %d times", removeFired)
+	}
+
+	posAndVel, err := storage.NewOrExistingArchetype(posComp, velComp)
+	if err != nil {
+		t.Fatalf("Failed to resolve archetype: %v", err)
+	}
+	posAndVel.OnPut(func(e Entity) { putFired++ })
+
+	if err := entity.RemoveComponent(velComp); err != nil {
+		t.Fatalf("RemoveComponent failed: %v", err)
+	}
+	if err := entity.AddComponent(velComp); err != nil {
+		t.Fatalf("AddComponent failed: %v", err)
+	}
+	if putFired != 1 {
+		t.Errorf("expected OnPut to fire once entering the position+velocity archetype, fired %d times", putFired)
+	}
+}
+
+// TestArchetypeTriggersOnReplace tests that OnReplace fires on both the
+// origin and destination archetype of a move
+func TestArchetypeTriggersOnReplace(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	entity := entities[0]
+
+	posOnly, err := storage.NewOrExistingArchetype(posComp)
+	if err != nil {
+		t.Fatalf("Failed to resolve archetype: %v", err)
+	}
+	var fired int
+	posOnly.OnReplace(func(e Entity, from, to Archetype) { fired++ })
+
+	if err := entity.AddComponent(velComp); err != nil {
+		t.Fatalf("AddComponent failed: %v", err)
+	}
+	if fired != 1 {
+		t.Errorf("expected OnReplace to fire once for the origin archetype, fired %d times", fired)
+	}
+}
+
+// TestArchetypeTriggersDeferWhenLocked tests that triggers fire only once
+// storage unlocks when the move happens while a queued operation is
+// draining, so trigger bodies can safely call AddComponent themselves
+func TestArchetypeTriggersDeferWhenLocked(t *testing.T) {
+	schema := table.Factory.NewSchema()
+	storage := Factory.NewStorage(schema)
+	posComp := FactoryNewComponent[Position]()
+	velComp := FactoryNewComponent[Velocity]()
+	healthComp := FactoryNewComponent[Health]()
+
+	entities, err := storage.NewEntities(1, posComp)
+	if err != nil {
+		t.Fatalf("Failed to create entities: %v", err)
+	}
+	entity := entities[0]
+
+	posAndVel, err := storage.NewOrExistingArchetype(posComp, velComp)
+	if err != nil {
+		t.Fatalf("Failed to resolve archetype: %v", err)
+	}
+	posAndVel.OnPut(func(e Entity) {
+		if err := e.AddComponent(healthComp); err != nil {
+			t.Errorf("AddComponent from trigger failed: %v", err)
+		}
+	})
+
+	storage.AddLock(1)
+	if err := entity.EnqueueAddComponent(velComp); err != nil {
+		t.Fatalf("EnqueueAddComponent failed: %v", err)
+	}
+	storage.RemoveLock(1)
+
+	if !healthComp.Accessor.Check(entity.Table()) {
+		t.Errorf("expected the trigger's own AddComponent to have applied once the queue drained")
+	}
+}
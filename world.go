@@ -0,0 +1,30 @@
+package warehouse
+
+// World aggregates multiple storages and drains their deferred operation
+// queues in a single deterministic order: the order storages were added to
+// the World. This gives cross-storage command sequences (e.g. transferring
+// an entity from a staging storage into a live one) a stable apply order
+// instead of racing on whichever storage happens to unlock first.
+type World struct {
+	storages []Storage
+}
+
+// Add registers a storage with the world, appending it to the drain order
+func (w *World) Add(sto Storage) *World {
+	w.storages = append(w.storages, sto)
+	return w
+}
+
+// ProcessAll drains every registered, unlocked storage's operation queue,
+// in the order storages were added to the World
+func (w *World) ProcessAll() error {
+	for _, sto := range w.storages {
+		if sto.Locked() {
+			continue
+		}
+		if err := sto.processQueue(); err != nil {
+			return err
+		}
+	}
+	return nil
+}